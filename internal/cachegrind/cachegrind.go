@@ -0,0 +1,244 @@
+// Package cachegrind reads the profiles Xdebug writes in the callgrind format.
+// See https://valgrind.org/docs/manual/cl-format.html for the full format.
+package cachegrind
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Function is the cost of a single function across a profile.
+type Function struct {
+	Name      string        `json:"name"`
+	File      string        `json:"file"`
+	Calls     int64         `json:"calls"`
+	Self      time.Duration `json:"self"`
+	Inclusive time.Duration `json:"inclusive"`
+}
+
+// Profile is a parsed profile.
+type Profile struct {
+	Command   string        `json:"command"`
+	Total     time.Duration `json:"total"`
+	Functions []Function    `json:"functions"`
+}
+
+// compressedName matches names that may be written once as "(id) name" and referenced afterwards as "(id)".
+var compressedName = regexp.MustCompile(`^\((\d+)\)(?: (.*))?$`)
+
+type parser struct {
+	timeUnit  time.Duration
+	names     map[string]map[string]string
+	functions map[string]*Function
+	current   *Function
+	callee    string
+	inCall    bool
+	calls     int64
+	total     time.Duration
+	command   string
+}
+
+// Parse Reads a profile and totals the time spent in each function.
+func Parse(reader io.Reader) (*Profile, error) {
+	p := &parser{
+		timeUnit:  10 * time.Nanosecond,
+		names:     map[string]map[string]string{},
+		functions: map[string]*Function{},
+	}
+
+	scanner := bufio.NewScanner(reader)
+
+	// Long function and file names are common in PHP projects
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	file := ""
+	callFile := ""
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, isSpec := strings.Cut(line, "=")
+		if isSpec && !strings.ContainsAny(key, " ") {
+			switch key {
+			case "fl", "fi", "fe":
+				file = p.resolveName("fl", value)
+			case "fn":
+				p.setFunction(p.resolveName("fn", value), file)
+			case "cfl", "cfi":
+				callFile = p.resolveName("fl", value)
+			case "cfn":
+				p.callee = p.resolveName("fn", value)
+				p.ensureFunction(p.callee, callFile)
+			case "calls":
+				count, err := strconv.ParseInt(strings.Fields(value)[0], 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("invalid calls line %q: %s", line, err)
+				}
+
+				p.inCall = true
+				p.calls = count
+			}
+
+			continue
+		}
+
+		header, headerValue, isHeader := strings.Cut(line, ":")
+		if isHeader && !strings.ContainsAny(header, " ") {
+			p.parseHeader(header, strings.TrimSpace(headerValue))
+			continue
+		}
+
+		err := p.parseCost(line)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return p.profile(), nil
+}
+
+// Top Returns the functions that took the most inclusive time, most expensive first.
+func (p *Profile) Top(count int) []Function {
+	functions := append([]Function{}, p.Functions...)
+
+	sort.SliceStable(functions, func(i, j int) bool {
+		return functions[i].Inclusive > functions[j].Inclusive
+	})
+
+	if count > 0 && len(functions) > count {
+		functions = functions[:count]
+	}
+
+	return functions
+}
+
+func (p *parser) ensureFunction(name, file string) *Function {
+	function, ok := p.functions[name]
+	if !ok {
+		function = &Function{Name: name, File: file}
+		p.functions[name] = function
+	}
+
+	if function.File == "" {
+		function.File = file
+	}
+
+	return function
+}
+
+func (p *parser) parseCost(line string) error {
+	if p.current == nil {
+		return nil
+	}
+
+	fields := strings.Fields(line)
+
+	// The first field is the position and the first event is always the time
+	if len(fields) < 2 {
+		return nil
+	}
+
+	cost, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid cost line %q: %s", line, err)
+	}
+
+	duration := time.Duration(cost) * p.timeUnit
+
+	// The cost following a calls line is the inclusive cost of that call
+	if p.inCall {
+		p.current.Inclusive += duration
+		p.ensureFunction(p.callee, "").Calls += p.calls
+		p.inCall = false
+
+		return nil
+	}
+
+	p.current.Self += duration
+	p.current.Inclusive += duration
+
+	return nil
+}
+
+func (p *parser) parseHeader(header, value string) {
+	switch header {
+	case "cmd":
+		p.command = value
+	case "events":
+		// Xdebug 3 records time in units of 10ns where Xdebug 2 used microseconds
+		if events := strings.Fields(value); len(events) > 0 && events[0] == "Time" {
+			p.timeUnit = time.Microsecond
+		}
+	case "summary", "totals":
+		if fields := strings.Fields(value); len(fields) > 0 {
+			total, err := strconv.ParseInt(fields[0], 10, 64)
+			if err == nil {
+				p.total = time.Duration(total) * p.timeUnit
+			}
+		}
+	}
+}
+
+func (p *parser) profile() *Profile {
+	profile := &Profile{
+		Command:   p.command,
+		Total:     p.total,
+		Functions: make([]Function, 0, len(p.functions)),
+	}
+
+	for _, function := range p.functions {
+		profile.Functions = append(profile.Functions, *function)
+	}
+
+	sort.Slice(profile.Functions, func(i, j int) bool {
+		return profile.Functions[i].Name < profile.Functions[j].Name
+	})
+
+	// Older profiles don't include a summary so fall back to the most expensive function
+	if profile.Total == 0 {
+		for _, function := range profile.Functions {
+			if function.Inclusive > profile.Total {
+				profile.Total = function.Inclusive
+			}
+		}
+	}
+
+	return profile
+}
+
+// resolveName Expands compressed names, remembering the ones defined for later references.
+func (p *parser) resolveName(kind, value string) string {
+	match := compressedName.FindStringSubmatch(value)
+	if match == nil {
+		return value
+	}
+
+	if p.names[kind] == nil {
+		p.names[kind] = map[string]string{}
+	}
+
+	if match[2] != "" {
+		p.names[kind][match[1]] = match[2]
+	}
+
+	return p.names[kind][match[1]]
+}
+
+func (p *parser) setFunction(name, file string) {
+	p.current = p.ensureFunction(name, file)
+	p.inCall = false
+}
@@ -0,0 +1,115 @@
+package cachegrind
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const xdebugProfile = `version: 1
+creator: xdebug 3.3.2 (PHP 8.4.1)
+cmd: /var/www/html/index.php
+part: 1
+positions: line
+
+events: Time_(10ns) Memory_(bytes)
+
+fl=(1) php:internal
+fn=(1) php::microtime
+12 100 32
+
+fl=(2) /var/www/html/wp-content/plugins/slow/slow.php
+fn=(2) slow_hook
+5 4000 128
+cfl=(1)
+cfn=(1)
+calls=2 0 0
+7 200 64
+
+fl=(3) /var/www/html/wp-includes/class-wp-hook.php
+fn=(3) WP_Hook->apply_filters
+300 1000 0
+cfl=(2)
+cfn=(2)
+calls=1 0 0
+310 4200 192
+
+fl=(4) /var/www/html/index.php
+fn=(4) {main}
+1 500 0
+cfl=(3)
+cfn=(3)
+calls=1 0 0
+17 5200 192
+
+summary: 5700 192
+`
+
+func TestParse(t *testing.T) {
+	profile, err := Parse(strings.NewReader(xdebugProfile))
+	if err != nil {
+		t.Fatalf("Parse returned an error: %v", err)
+	}
+
+	if profile.Command != "/var/www/html/index.php" {
+		t.Errorf("Unexpected command: %s", profile.Command)
+	}
+
+	if profile.Total != 57*time.Microsecond {
+		t.Errorf("Expected a total of 57µs, got %s", profile.Total)
+	}
+
+	top := profile.Top(2)
+
+	if len(top) != 2 {
+		t.Fatalf("Expected 2 functions, got %d", len(top))
+	}
+
+	if top[0].Name != "{main}" || top[0].Inclusive != 57*time.Microsecond || top[0].Self != 5*time.Microsecond {
+		t.Errorf("Unexpected first function: %+v", top[0])
+	}
+
+	if top[1].Name != "WP_Hook->apply_filters" || top[1].Calls != 1 {
+		t.Errorf("Unexpected second function: %+v", top[1])
+	}
+
+	for _, function := range profile.Functions {
+		switch function.Name {
+		case "slow_hook":
+			if function.Self != 40*time.Microsecond || function.Inclusive != 42*time.Microsecond {
+				t.Errorf("Unexpected cost for slow_hook: %+v", function)
+			}
+
+			if function.File != "/var/www/html/wp-content/plugins/slow/slow.php" {
+				t.Errorf("Unexpected file for slow_hook: %s", function.File)
+			}
+		case "php::microtime":
+			if function.Calls != 2 || function.Self != time.Microsecond {
+				t.Errorf("Unexpected cost for php::microtime: %+v", function)
+			}
+		}
+	}
+}
+
+func TestParseXdebug2(t *testing.T) {
+	profile, err := Parse(strings.NewReader(`events: Time Memory
+
+fl=/var/www/html/index.php
+fn={main}
+1 250 0
+`))
+	if err != nil {
+		t.Fatalf("Parse returned an error: %v", err)
+	}
+
+	if profile.Total != 250*time.Microsecond {
+		t.Errorf("Expected a total of 250µs, got %s", profile.Total)
+	}
+}
+
+func TestParseInvalidCost(t *testing.T) {
+	_, err := Parse(strings.NewReader("fn=(1) main\n1 abc\n"))
+	if err == nil {
+		t.Error("Expected an error for an invalid cost line")
+	}
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/settings"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func archive(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Create and restore portable archives of a complete site",
+		Args:  cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	createCmd := &cobra.Command{
+		Use:   "create [archive file]",
+		Short: "Archive the site's wp-content folder, database and settings to a single .tar.gz file",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureRunningSite(kanaSite, consoleOutput)
+
+			file, err := kanaSite.CreateArchive(args, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(fmt.Sprintf("Your site has been archived to %s.", file))
+		},
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore <archive file>",
+		Short: "Recreate a site from an archive in the current folder or under the given name",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if kanaSite.IsSiteRunning() {
+				consoleOutput.Error(fmt.Errorf("the site is already running. Please stop your site before restoring an archive"))
+			}
+
+			if !kanaSettings.GetBool("isNew") && !flagForce {
+				confirmRestore := consoleOutput.PromptConfirm(
+					fmt.Sprintf(
+						"The site %s already exists. Are you sure you want to replace its content with the archive? %s",
+						consoleOutput.Bold(consoleOutput.Blue(kanaSettings.Get("name"))),
+						consoleOutput.Bold(consoleOutput.Yellow("This operation is destructive and cannot be undone."))),
+					false)
+				if !confirmRestore {
+					consoleOutput.Error(fmt.Errorf("archive restore canceled. No data has been lost"))
+				}
+			}
+
+			err = kanaSite.RestoreArchive(args[0], consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(
+				fmt.Sprintf(
+					"Your site, %s, has been restored and should be open in your default browser.",
+					consoleOutput.Bold(consoleOutput.Blue(kanaSettings.Get("name")))))
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	restoreCmd.Flags().BoolVar(&flagForce, "force", false, "Replace the content of an existing site without a prompt.")
+
+	cmd.AddCommand(
+		createCmd,
+		restoreCmd,
+	)
+
+	return cmd
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/settings"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func cert(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cert",
+		Short: "Inspects, renews and replaces the SSL certificates Kana serves sites with.",
+		Args:  cobra.NoArgs,
+	}
+
+	infoCommand := &cobra.Command{
+		Use:   "info",
+		Short: "Shows the subject, domains, expiry and fingerprint of each of Kana's certificates.",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			certificates, err := settings.GetCertificates(kanaSettings.Get("appDirectory"))
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if consoleOutput.JSON {
+				str, _ := json.Marshal(certificates)
+
+				fmt.Println(string(str))
+
+				return
+			}
+
+			for i := range certificates {
+				if i > 0 {
+					consoleOutput.Println("")
+				}
+
+				outputCertificate(&certificates[i], consoleOutput)
+			}
+		},
+	}
+
+	renewCommand := &cobra.Command{
+		Use:   "renew",
+		Short: "Signs new site certificates with the existing root certificate and restarts Traefik to use them.",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			err = settings.RenewCerts(kanaSettings.Get("appDirectory"))
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			err = kanaSite.RestartTraefik()
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success("Kana's site certificates have been renewed.")
+		},
+	}
+
+	rotateCommand := &cobra.Command{
+		Use:   "rotate-ca",
+		Short: "Replaces Kana's root certificate, signs every site certificate again and trusts the new root certificate.",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			appDirectory := kanaSettings.Get("appDirectory")
+
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			canTrust := runtime.GOOS == "darwin" || runtime.GOOS == "linux"
+
+			// The old root certificate is needed to find it in the trust stores
+			if canTrust {
+				err = settings.UntrustSSL(kanaSettings.Get("rootCert"), appDirectory, consoleOutput)
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+			}
+
+			err = settings.RotateCA(appDirectory)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if canTrust {
+				err = settings.TrustSSL(kanaSettings.Get("rootCert"), appDirectory, consoleOutput)
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+			}
+
+			err = kanaSite.RestartTraefik()
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success("Kana's root certificate has been replaced. Restart your browser to use the new certificates.")
+		},
+	}
+
+	cmd.AddCommand(infoCommand, renewCommand, rotateCommand)
+
+	return cmd
+}
+
+// outputCertificate Prints the details of a certificate.
+func outputCertificate(certificate *settings.CertificateInfo, consoleOutput *console.Console) {
+	name := certificate.Name
+
+	switch certificate.Name {
+	case "root":
+		name = "Root CA"
+	case "shared":
+		name = "Shared certificate"
+	}
+
+	expiry := consoleOutput.Green(certificate.NotAfter.Format(time.DateOnly))
+	daysLeft := int(time.Until(certificate.NotAfter).Hours() / 24)
+
+	switch {
+	case daysLeft < 0:
+		expiry = consoleOutput.Red(fmt.Sprintf("%s (expired)", certificate.NotAfter.Format(time.DateOnly)))
+	case daysLeft <= 30:
+		expiry = consoleOutput.Yellow(fmt.Sprintf("%s (%d days left)", certificate.NotAfter.Format(time.DateOnly), daysLeft))
+	}
+
+	consoleOutput.Println(consoleOutput.Bold(name))
+	consoleOutput.Println(fmt.Sprintf("File: %s", certificate.File))
+	consoleOutput.Println(fmt.Sprintf("Subject: %s", certificate.Subject))
+	consoleOutput.Println(fmt.Sprintf("Issuer: %s", certificate.Issuer))
+	consoleOutput.Println(fmt.Sprintf("Key: %s", certificate.KeyType))
+
+	if len(certificate.DNSNames) > 0 || len(certificate.IPAddresses) > 0 {
+		consoleOutput.Println(fmt.Sprintf("SANs: %s", strings.Join(append(certificate.DNSNames, certificate.IPAddresses...), ", ")))
+	}
+
+	consoleOutput.Println(fmt.Sprintf("Valid from: %s", certificate.NotBefore.Format(time.DateOnly)))
+	consoleOutput.Println(fmt.Sprintf("Expires: %s", expiry))
+	consoleOutput.Println(fmt.Sprintf("SHA-256: %s", certificate.SHA256))
+}
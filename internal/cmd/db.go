@@ -11,6 +11,8 @@ import (
 
 var flagPreserve bool
 var flagReplaceDomain string
+var flagMigrateTo string
+var flagMigrateVersion string
 
 func db(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
 	cmd := &cobra.Command{
@@ -63,15 +65,54 @@ func db(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
 
 	commandsRequiringSite = append(commandsRequiringSite, exportCmd.Use)
 
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Move the site's content to a different database server",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureRunningSite(kanaSite, consoleOutput)
+
+			err := kanaSite.MigrateDatabase(flagMigrateTo, flagMigrateVersion, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(
+				fmt.Sprintf("Your site has been migrated to %s and every table has been verified.", consoleOutput.Bold(flagMigrateTo)))
+		},
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			switch flagMigrateTo {
+			case "mariadb", "mysql", "sqlite":
+				return nil
+			}
+
+			return fmt.Errorf("the database to migrate to must be one of mariadb, mysql or sqlite")
+		},
+		Args: cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, migrateCmd.Use)
+
 	importCmd.Flags().BoolVarP(&flagPreserve, "preserve", "p", false, "Preserve the existing database (don't drop it before import)")
 	importCmd.Flags().StringVar(&flagReplaceDomain,
 		"replace-domain",
 		"",
 		"The old site domain to replace automatically with the development site domain")
 
+	migrateCmd.Flags().StringVar(&flagMigrateTo, "to", "", "The database server to migrate to (mariadb, mysql or sqlite)")
+	migrateCmd.Flags().StringVar(&flagMigrateVersion,
+		"database-version",
+		"",
+		"The version of the new database server (defaults to the latest supported version)")
+
+	err := migrateCmd.MarkFlagRequired("to")
+	if err != nil {
+		consoleOutput.Error(err)
+	}
+
 	cmd.AddCommand(
 		importCmd,
 		exportCmd,
+		migrateCmd,
 	)
 
 	return cmd
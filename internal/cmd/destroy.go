@@ -46,6 +46,12 @@ func destroy(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *
 					consoleOutput.Error(err)
 				}
 
+				// Remove the certificate and hosts entries for any extra domains.
+				err = kanaSite.RemoveDomains(consoleOutput)
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+
 				// Remove the site's folder in the config directory.
 				err = os.RemoveAll(kanaSettings.Get("siteDirectory"))
 				if err != nil {
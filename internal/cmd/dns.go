@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/settings"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func dns(consoleOutput *console.Console, kanaSite *site.Site, kanaSettings *settings.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dns",
+		Short: "Shows whether Kana's local DNS server is running and in use.",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			state := consoleOutput.Red("Stopped")
+
+			if kanaSite.IsDNSRunning() {
+				state = consoleOutput.Green("Running")
+			}
+
+			consoleOutput.Println(fmt.Sprintf("DNS server: %s on 127.0.0.1:%d", state, kanaSettings.GetInt("dnsPort")))
+			consoleOutput.Println(fmt.Sprintf("Resolver configured: %s", formatStatusFlag(settings.IsDNSResolverInstalled(), consoleOutput)))
+
+			dnsZones, err := settings.GetDNSZones(kanaSettings.Get("appDirectory"))
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			for _, dnsZone := range dnsZones {
+				consoleOutput.Println(fmt.Sprintf("Domain: %s", dnsZone))
+			}
+		},
+	}
+
+	setupCommand := &cobra.Command{
+		Use:   "setup",
+		Short: "Starts Kana's DNS server with your sites and points systemd-resolved at it so sites work without internet.",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			err = kanaSettings.Set("dns", true, true)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			err = kanaSite.StartDNS(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			err = settings.InstallDNSResolver(kanaSettings.Get("appDirectory"), int(kanaSettings.GetInt("dnsPort")), consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success("Kana's domains are now answered by its local DNS server.")
+		},
+	}
+
+	removeCommand := &cobra.Command{
+		Use:   "remove",
+		Short: "Stops Kana's DNS server and removes the systemd-resolved configuration pointing at it.",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			err = settings.RemoveDNSResolver(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			err = kanaSite.StopDNS()
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			err = kanaSettings.Set("dns", false, true)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success("Kana's domains are now looked up with your regular DNS.")
+		},
+	}
+
+	cmd.AddCommand(setupCommand, removeCommand)
+
+	return cmd
+}
@@ -33,6 +33,11 @@ func flush(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
 				}
 			}
 
+			err = kanaSite.FlushRedis()
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
 			consoleOutput.Success("Cache and transients have been successfully flushed")
 		},
 		Args: cobra.NoArgs,
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/ChrisWiegman/kana/internal/console"
 	"github.com/ChrisWiegman/kana/internal/site"
@@ -43,7 +44,7 @@ func list(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
 
 			t := table.New(os.Stdout)
 
-			t.SetHeaders("Name", "Path", "Status")
+			t.SetHeaders("Name", "Path", "Status", "Services")
 
 			for _, site := range sites {
 				path := site.Path
@@ -58,7 +59,7 @@ func list(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
 					status = consoleOutput.Green("Running")
 				}
 
-				t.AddRow(site.Name, path, status)
+				t.AddRow(site.Name, path, status, strings.Join(site.Services, "\n"))
 			}
 
 			t.Render()
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/docker"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagLogsFollow bool
+var flagLogsSince, flagLogsTail, flagLogsGrep string
+
+func logs(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:       "logs [wordpress|database|mailpit|phpmyadmin|redis|traefik]",
+		Short:     "View the container logs for the current site's services.",
+		ValidArgs: site.GetLogServices(),
+		Args:      cobra.OnlyValidArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			options := docker.LogOptions{
+				Follow: flagLogsFollow,
+				Since:  flagLogsSince,
+				Tail:   flagLogsTail,
+			}
+
+			err = kanaSite.StreamLogs(args, options, flagLogsGrep, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+		},
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	cmd.Flags().BoolVarP(&flagLogsFollow, "follow", "f", false, "Keep streaming new log output as it is written")
+	cmd.Flags().StringVar(
+		&flagLogsSince,
+		"since",
+		"",
+		"Only show logs since a timestamp (e.g. 2025-01-02T13:23:37Z) or relative duration (e.g. 42m)")
+	cmd.Flags().StringVar(&flagLogsTail, "tail", "all", "Number of lines to show from the end of the logs for each service")
+	cmd.Flags().StringVar(&flagLogsGrep, "grep", "", "Only show log lines matching the given regular expression")
+
+	return cmd
+}
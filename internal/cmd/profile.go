@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/aquasecurity/table"
+	"github.com/spf13/cobra"
+)
+
+var flagProfileTrace bool
+var flagProfileTop int
+
+func profile(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile <url path>",
+		Short: "Profile a single request to the site with Xdebug and show the most expensive functions.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureRunningSite(kanaSite, consoleOutput)
+
+			result, err := kanaSite.ProfileRequest(args[0], flagProfileTrace, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(fmt.Errorf("unable to profile %s: %s", result.URL, err))
+			}
+
+			if consoleOutput.JSON {
+				result.Profile.Functions = result.Profile.Top(flagProfileTop)
+
+				str, _ := json.Marshal(result)
+
+				fmt.Println(string(str))
+
+				return
+			}
+
+			consoleOutput.Println(
+				fmt.Sprintf("Profiled %s (status %d) in %s", result.URL, result.StatusCode, formatProfileTime(result.Profile.Total)))
+			consoleOutput.Println(fmt.Sprintf("Profile: %s", consoleOutput.Bold(result.File)))
+
+			if result.TraceFile != "" {
+				consoleOutput.Println(fmt.Sprintf("Trace: %s", consoleOutput.Bold(result.TraceFile)))
+			}
+
+			t := table.New(os.Stdout)
+
+			t.SetHeaders("Function", "Calls", "Inclusive", "Self", "% of Total")
+
+			for _, function := range result.Profile.Top(flagProfileTop) {
+				percent := 0.0
+
+				if result.Profile.Total > 0 {
+					percent = float64(function.Inclusive) / float64(result.Profile.Total) * 100
+				}
+
+				t.AddRow(
+					function.Name,
+					strconv.FormatInt(function.Calls, 10),
+					formatProfileTime(function.Inclusive),
+					formatProfileTime(function.Self),
+					fmt.Sprintf("%.1f%%", percent))
+			}
+
+			t.Render()
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	cmd.Flags().BoolVarP(&flagProfileTrace, "trace", "t", false, "Write a function trace of the request alongside the profile")
+	cmd.Flags().IntVar(&flagProfileTop, "top", 20, "The number of functions to show, sorted by inclusive time. Use 0 to show them all")
+
+	return cmd
+}
+
+// formatProfileTime Shows profile times in milliseconds, which is the most useful resolution for a web request.
+func formatProfileTime(duration time.Duration) string {
+	return fmt.Sprintf("%.2fms", float64(duration)/float64(time.Millisecond))
+}
@@ -61,22 +61,33 @@ func Execute() {
 
 	// Register the subcommands
 	cmd.AddCommand(
+		archive(consoleOutput, kanaSite, kanaSettings),
+		cert(consoleOutput, kanaSite, kanaSettings),
 		changelog(consoleOutput),
 		config(consoleOutput, kanaSettings),
 		db(consoleOutput, kanaSite),
 		destroy(consoleOutput, kanaSite, kanaSettings),
+		dns(consoleOutput, kanaSite, kanaSettings),
 		export(consoleOutput, kanaSite, kanaSettings),
 		flush(consoleOutput, kanaSite),
 		list(consoleOutput, kanaSite),
+		logs(consoleOutput, kanaSite),
 		open(consoleOutput, kanaSite, kanaSettings),
+		profile(consoleOutput, kanaSite),
+		shell(consoleOutput, kanaSite),
+		snapshot(consoleOutput, kanaSite),
 		start(consoleOutput, kanaSite, kanaSettings),
+		status(consoleOutput, kanaSite),
 		stop(consoleOutput, kanaSite, kanaSettings),
+		test(consoleOutput, kanaSite),
 		version(consoleOutput),
 		wp(consoleOutput, kanaSite),
 		xdebug(consoleOutput, kanaSite),
 	)
 
-	if runtime.GOOS == "darwin" {
+	cmd.AddCommand(toolCommands(consoleOutput, kanaSite)...)
+
+	if runtime.GOOS == "darwin" || runtime.GOOS == "linux" {
 		cmd.AddCommand(trust(consoleOutput, kanaSettings))
 	}
 
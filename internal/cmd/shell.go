@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+func shell(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "shell [wordpress|database|cli]",
+		Short: "Open an interactive shell in one of the site's containers (defaults to wordpress).",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureRunningSite(kanaSite, consoleOutput)
+
+			service := "wordpress"
+
+			if len(args) == 1 {
+				service = args[0]
+			}
+
+			code, err := kanaSite.OpenShell(service, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			// Return the status of the last command run in the shell
+			if code != 0 {
+				os.Exit(int(code))
+			}
+		},
+		Args:      cobra.MatchAll(cobra.MaximumNArgs(1), cobra.OnlyValidArgs),
+		ValidArgs: site.GetShellServices(),
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	return cmd
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/aquasecurity/table"
+	"github.com/spf13/cobra"
+)
+
+var flagSnapshotSource string
+
+func snapshot(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Save, restore and manage named snapshots of the site's database",
+		Args:  cobra.NoArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	saveCmd := &cobra.Command{
+		Use:   "save [name]",
+		Short: "Save a compressed snapshot of the site's database",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureRunningSite(kanaSite, consoleOutput)
+
+			name := ""
+
+			if len(args) == 1 {
+				name = args[0]
+			}
+
+			manifest, err := kanaSite.SaveSnapshot(name, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(fmt.Sprintf("Your database has been saved to the snapshot %s.", consoleOutput.Bold(manifest.Name)))
+		},
+		Args: cobra.MaximumNArgs(1),
+	}
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore <name>",
+		Short: "Restore the site's database from a saved snapshot",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureRunningSite(kanaSite, consoleOutput)
+
+			err := kanaSite.RestoreSnapshot(args[0], flagSnapshotSource, flagPreserve, flagReplaceDomain, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(
+				fmt.Sprintf("The snapshot %s has been restored. Reload your site to see the changes.", consoleOutput.Bold(args[0])))
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the saved database snapshots for the site",
+		Run: func(cmd *cobra.Command, args []string) {
+			snapshots, err := kanaSite.GetSnapshotList()
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if consoleOutput.JSON {
+				str, _ := json.Marshal(snapshots)
+
+				fmt.Println(string(str))
+
+				return
+			}
+
+			t := table.New(os.Stdout)
+
+			t.SetHeaders("Name", "Created", "WordPress", "PHP", "Database", "Active Plugins")
+
+			for i := range snapshots {
+				t.AddRow(
+					snapshots[i].Name,
+					snapshots[i].Created.Format("2006-01-02 15:04:05"),
+					snapshots[i].WordPressVersion,
+					snapshots[i].PHPVersion,
+					fmt.Sprintf("%s %s", snapshots[i].Database, snapshots[i].DatabaseVersion),
+					strconv.Itoa(len(snapshots[i].Plugins)))
+			}
+
+			t.Render()
+		},
+		Args: cobra.NoArgs,
+	}
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a saved database snapshot",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.DeleteSnapshot(args[0])
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success(fmt.Sprintf("The snapshot %s has been deleted.", consoleOutput.Bold(args[0])))
+		},
+		Args: cobra.ExactArgs(1),
+	}
+
+	restoreCmd.Flags().BoolVarP(&flagPreserve, "preserve", "p", false, "Preserve the existing database (don't drop it before restoring)")
+	restoreCmd.Flags().StringVar(&flagReplaceDomain,
+		"replace-domain",
+		"",
+		"The old site domain to replace with the development site domain (defaults to the domain the snapshot was saved from)")
+	restoreCmd.Flags().StringVar(&flagSnapshotSource, "from", "", "The name of the site the snapshot was saved from, if not the current site")
+
+	cmd.AddCommand(
+		saveCmd,
+		restoreCmd,
+		listCmd,
+		deleteCmd,
+	)
+
+	return cmd
+}
+
+// ensureRunningSite Exits with an error if Docker isn't available or the site isn't running.
+func ensureRunningSite(kanaSite *site.Site, consoleOutput *console.Console) {
+	err := kanaSite.EnsureDocker(consoleOutput)
+	if err != nil {
+		consoleOutput.Error(err)
+	}
+
+	if !kanaSite.IsSiteRunning() {
+		consoleOutput.Error(fmt.Errorf("this command only works on a running site. Please run 'kana start' to start the site"))
+	}
+}
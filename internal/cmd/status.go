@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/aquasecurity/table"
+	"github.com/docker/go-units"
+	"github.com/spf13/cobra"
+)
+
+func status(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Shows the state of the site's containers, the versions it is running and where to reach it.",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := kanaSite.EnsureDocker(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			siteStatus, err := kanaSite.GetSiteStatus(consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			if consoleOutput.JSON {
+				str, _ := json.Marshal(siteStatus)
+
+				fmt.Println(string(str))
+
+				return
+			}
+
+			state := consoleOutput.Red("Stopped")
+
+			if siteStatus.Running {
+				state = consoleOutput.Green("Running")
+			}
+
+			consoleOutput.Println(fmt.Sprintf("Site: %s (%s)", consoleOutput.Bold(siteStatus.Name), state))
+
+			if siteStatus.Path != "" {
+				consoleOutput.Println(fmt.Sprintf("Path: %s", siteStatus.Path))
+			}
+
+			consoleOutput.Println(fmt.Sprintf("Disk usage: %s WordPress, %s database",
+				units.HumanSize(float64(siteStatus.SiteDirectorySize)),
+				units.HumanSize(float64(siteStatus.DatabaseDirectorySize))))
+
+			if siteStatus.Running {
+				database := fmt.Sprintf("%s %s", siteStatus.Database, siteStatus.DatabaseVersion)
+
+				if siteStatus.DatabasePort != "" {
+					database = fmt.Sprintf("%s on port %s", database, siteStatus.DatabasePort)
+				}
+
+				xdebug := formatStatusFlag(siteStatus.Xdebug, consoleOutput)
+
+				if siteStatus.Xdebug {
+					xdebug = fmt.Sprintf("%s (%s)", xdebug, siteStatus.XdebugMode)
+				}
+
+				consoleOutput.Println(fmt.Sprintf("WordPress: %s", siteStatus.WordPressVersion))
+				consoleOutput.Println(fmt.Sprintf("PHP: %s", siteStatus.PHPVersion))
+				consoleOutput.Println(fmt.Sprintf("Database: %s", strings.TrimSpace(database)))
+				consoleOutput.Println(fmt.Sprintf("Xdebug: %s", xdebug))
+				consoleOutput.Println(fmt.Sprintf("WP_DEBUG: %s", formatStatusFlag(siteStatus.WPDebug, consoleOutput)))
+				consoleOutput.Println(fmt.Sprintf("SCRIPT_DEBUG: %s", formatStatusFlag(siteStatus.ScriptDebug, consoleOutput)))
+
+				for _, statusURL := range siteStatus.URLs {
+					consoleOutput.Println(fmt.Sprintf("%s: %s", statusURL.Name, consoleOutput.Bold(statusURL.URL)))
+				}
+			}
+
+			if len(siteStatus.Containers) == 0 {
+				return
+			}
+
+			t := table.New(os.Stdout)
+
+			t.SetHeaders("Container", "Image", "State", "Health", "Ports")
+
+			for _, containerStatus := range siteStatus.Containers {
+				t.AddRow(
+					containerStatus.Service,
+					containerStatus.Image,
+					containerStatus.Status,
+					formatStatusHealth(containerStatus.Health, consoleOutput),
+					strings.Join(containerStatus.Ports, "\n"))
+			}
+
+			t.Render()
+		},
+		Args: cobra.NoArgs,
+	}
+
+	return cmd
+}
+
+// formatStatusFlag Shows whether a debugging option is on or off.
+func formatStatusFlag(isOn bool, consoleOutput *console.Console) string {
+	if isOn {
+		return consoleOutput.Green("on")
+	}
+
+	return "off"
+}
+
+// formatStatusHealth Colors the health of a container by how worried the user should be.
+func formatStatusHealth(health string, consoleOutput *console.Console) string {
+	switch health {
+	case "healthy":
+		return consoleOutput.Green(health)
+	case "unhealthy":
+		return consoleOutput.Red(health)
+	case "starting":
+		return consoleOutput.Yellow(health)
+	}
+
+	return health
+}
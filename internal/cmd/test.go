@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+var flagJUnit string
+
+func test(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test [-- phpunit arguments]",
+		Short: "Run the plugin or theme's PHPUnit tests against the WordPress test library.",
+		Run: func(cmd *cobra.Command, args []string) {
+			ensureRunningSite(kanaSite, consoleOutput)
+
+			code, err := kanaSite.RunTests(args, flagJUnit, consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			// Pass PHPUnit's result on so the command can be used in git hooks and CI
+			if code != 0 {
+				os.Exit(int(code))
+			}
+		},
+		Args: cobra.ArbitraryArgs,
+	}
+
+	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
+
+	cmd.Flags().StringVar(&flagJUnit, "junit", "", "Write the test results as JUnit XML to the given file in the project directory")
+
+	return cmd
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/site"
+
+	"github.com/spf13/cobra"
+)
+
+// toolCommands Returns a command for each of the tools, such as Composer and npm, that Kana can run in a container.
+func toolCommands(consoleOutput *console.Console, kanaSite *site.Site) []*cobra.Command {
+	commands := []*cobra.Command{}
+
+	for _, tool := range site.GetTools() {
+		cmd := &cobra.Command{
+			Use:   tool.Name,
+			Short: fmt.Sprintf("Run %s in the current directory without installing it locally.", tool.Name),
+			Run: func(cmd *cobra.Command, args []string) {
+				err := kanaSite.EnsureDocker(consoleOutput)
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+
+				code, err := kanaSite.RunTool(tool, args, consoleOutput)
+				if err != nil {
+					consoleOutput.Error(err)
+				}
+
+				if code != 0 {
+					os.Exit(int(code))
+				}
+			},
+			Args: cobra.ArbitraryArgs,
+		}
+
+		// Pass all flags through to the tool
+		cmd.DisableFlagParsing = true
+
+		commands = append(commands, cmd)
+	}
+
+	return commands
+}
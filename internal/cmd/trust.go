@@ -10,14 +10,12 @@ import (
 func trust(consoleOutput *console.Console, kanaSettings *settings.Settings) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "trust-ssl",
-		Short: "Add the Kana SSL certificate to the MacOS Keychain (if needed).",
+		Short: "Add the Kana SSL certificate to your system's trusted certificates (if needed).",
 		Run: func(cmd *cobra.Command, args []string) {
 			appDirectory := kanaSettings.Get("appDirectory")
 
-			err := settings.EnsureSSLCerts(
-				appDirectory,
-				kanaSettings.GetBool("ssl"),
-				consoleOutput)
+			// The certificate is trusted below so only make sure it exists
+			_, err := settings.EnsureSSLCerts(appDirectory, false, consoleOutput)
 			if err != nil {
 				consoleOutput.Error(err)
 			}
@@ -27,12 +25,34 @@ func trust(consoleOutput *console.Console, kanaSettings *settings.Settings) *cob
 				consoleOutput.Error(err)
 			}
 
-			consoleOutput.Success("The Kana SSL certificate has been added to your Mac's system Keychain.")
+			err = settings.VerifySSLTrust(kanaSettings.Get("rootCert"), appDirectory)
+			if err != nil {
+				consoleOutput.Warn(err.Error())
+				return
+			}
+
+			consoleOutput.Success("The Kana SSL certificate has been added to your system's trusted certificates.")
 		},
 		Args: cobra.NoArgs,
 	}
 
 	commandsRequiringSite = append(commandsRequiringSite, cmd.Use)
 
+	untrustCommand := &cobra.Command{
+		Use:   "untrust",
+		Short: "Remove the Kana SSL certificate from your system's trusted certificates.",
+		Run: func(cmd *cobra.Command, args []string) {
+			err := settings.UntrustSSL(kanaSettings.Get("rootCert"), kanaSettings.Get("appDirectory"), consoleOutput)
+			if err != nil {
+				consoleOutput.Error(err)
+			}
+
+			consoleOutput.Success("The Kana SSL certificate has been removed from your system's trusted certificates.")
+		},
+		Args: cobra.NoArgs,
+	}
+
+	cmd.AddCommand(untrustCommand)
+
 	return cmd
 }
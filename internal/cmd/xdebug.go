@@ -7,6 +7,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var flagXdebugMode string
+
 func xdebug(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "xdebug [on/off]",
@@ -36,7 +38,7 @@ func xdebug(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command
 				consoleOutput.Error(err)
 			}
 
-			err = kanaSite.StartXdebug(consoleOutput)
+			err = kanaSite.StartXdebug(flagXdebugMode, consoleOutput)
 			if err != nil {
 				consoleOutput.Error(err)
 			}
@@ -49,6 +51,12 @@ func xdebug(consoleOutput *console.Console, kanaSite *site.Site) *cobra.Command
 
 	commandsRequiringSite = append(commandsRequiringSite, onCommand.Use)
 
+	onCommand.Flags().StringVar(
+		&flagXdebugMode,
+		"mode",
+		"",
+		"The Xdebug mode, such as debug or profile, to use instead of the xdebugMode setting")
+
 	offCommand := &cobra.Command{
 		Use:   "off",
 		Short: "Stops xdebug and removes its configuration",
@@ -36,6 +36,15 @@ func (c *Console) Bold(output string) string {
 	return aurora.Bold(output).String()
 }
 
+// Cyan outputs the requested text as cyan.
+func (c *Console) Cyan(output string) string {
+	if c.JSON {
+		return output
+	}
+
+	return aurora.Cyan(output).String()
+}
+
 // Error displays the error message and a panic if needed.
 func (c *Console) Error(err error) {
 	if c.JSON {
@@ -68,6 +77,15 @@ func (c *Console) Green(output string) string {
 	return aurora.Green(output).String()
 }
 
+// Magenta outputs the requested text as magenta.
+func (c *Console) Magenta(output string) string {
+	if c.JSON {
+		return output
+	}
+
+	return aurora.Magenta(output).String()
+}
+
 // Printf is a temporary wrapper on fmt.Printf.
 func (c *Console) Printf(format string, a ...any) {
 	if c.JSON {
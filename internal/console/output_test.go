@@ -19,6 +19,13 @@ func TestConsole_Bold(t *testing.T) {
 	assert.Equal(t, expected, output)
 }
 
+func TestConsole_Cyan(t *testing.T) {
+	console := &Console{}
+	output := console.Cyan("Hello, World!")
+	expected := "\x1b[36mHello, World!\x1b[0m"
+	assert.Equal(t, expected, output)
+}
+
 func TestConsole_Green(t *testing.T) {
 	console := &Console{}
 	output := console.Green("Hello, World!")
@@ -26,6 +33,13 @@ func TestConsole_Green(t *testing.T) {
 	assert.Equal(t, expected, output)
 }
 
+func TestConsole_Magenta(t *testing.T) {
+	console := &Console{}
+	output := console.Magenta("Hello, World!")
+	expected := "\x1b[35mHello, World!\x1b[0m"
+	assert.Equal(t, expected, output)
+}
+
 func TestConsole_Yellow(t *testing.T) {
 	console := &Console{}
 	output := console.Yellow("Hello, World!")
@@ -1,12 +1,10 @@
 package docker
 
 import (
-	"bufio"
 	"bytes"
 	"context"
 	"fmt"
 	"io"
-	"os"
 	"os/user"
 	"runtime"
 	"strings"
@@ -18,7 +16,6 @@ import (
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/strslice"
 	"github.com/docker/docker/pkg/stdcopy"
-	"golang.org/x/sync/errgroup"
 )
 
 type ContainerConfig struct {
@@ -29,8 +26,19 @@ type ContainerConfig struct {
 	NetworkName string
 	Volumes     []mount.Mount
 	Command     []string
+	Entrypoint  []string
 	Env         []string
 	Labels      map[string]string
+	WorkingDir  string
+	ExtraHosts  []string
+	HealthCheck *container.HealthConfig
+}
+
+// LogOptions represents the options available when reading the logs of a container.
+type LogOptions struct {
+	Follow bool
+	Since  string
+	Tail   string
 }
 
 type ExecResult struct {
@@ -143,6 +151,13 @@ func (d *Client) containerIsRunning(containerName string) (id string, isRunning
 	return "", false
 }
 
+// IsContainerRunning Returns true if a container with the given name is running.
+func (d *Client) IsContainerRunning(containerName string) bool {
+	_, isRunning := d.containerIsRunning(containerName)
+
+	return isRunning
+}
+
 // ContainerList Lists all running containers for a given site or all sites if no site is specified.
 func (d *Client) ContainerList(site string) ([]container.Summary, error) {
 	f := filters.NewArgs()
@@ -184,6 +199,40 @@ func (d *Client) containerLog(id string) (result string, err error) {
 	return string(buffer), nil
 }
 
+// ContainerLogs Streams the logs of the given container to the writer until they end or, if following, the container stops.
+func (d *Client) ContainerLogs(containerName string, options LogOptions, out io.Writer) error {
+	containerInfo, err := d.apiClient.ContainerInspect(context.Background(), containerName)
+	if err != nil {
+		return err
+	}
+
+	reader, err := d.apiClient.ContainerLogs(context.Background(), containerInfo.ID, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     options.Follow,
+		Since:      options.Since,
+		Tail:       options.Tail,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer reader.Close()
+
+	// Containers started with a TTY return a raw stream while all others need to be demultiplexed
+	if containerInfo.Config != nil && containerInfo.Config.Tty {
+		_, err = io.Copy(out, reader)
+	} else {
+		_, err = stdcopy.StdCopy(out, out, reader)
+	}
+
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
 func (d *Client) ContainerRestart(containerName string) (bool, error) {
 	containerID, isRunning := d.containerIsRunning(containerName)
 	if !isRunning {
@@ -209,10 +258,25 @@ func (d *Client) ContainerRun(config *ContainerConfig, randomPorts, localUser bo
 		return containerID, nil
 	}
 
+	containerID, err = d.containerCreate(config, randomPorts, localUser, true)
+	if err != nil {
+		return "", err
+	}
+
+	err = d.apiClient.ContainerStart(context.Background(), containerID, container.StartOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	return containerID, nil
+}
+
+// containerCreate Creates, but doesn't start, a container from the given config.
+func (d *Client) containerCreate(config *ContainerConfig, randomPorts, localUser, tty bool) (id string, err error) {
 	hostConfig := container.HostConfig{}
 	containerPorts, err := getNetworkConfig(config.Ports, randomPorts)
 	if err != nil {
-		return containerID, err
+		return "", err
 	}
 
 	if len(containerPorts.PortBindings) > 0 {
@@ -228,15 +292,19 @@ func (d *Client) ContainerRun(config *ContainerConfig, randomPorts, localUser bo
 	}
 
 	hostConfig.Mounts = config.Volumes
+	hostConfig.ExtraHosts = config.ExtraHosts
 
 	containerConfig := &container.Config{
-		Tty:          true,
+		Tty:          tty,
 		Image:        config.Image,
 		ExposedPorts: containerPorts.PortSet,
 		Cmd:          config.Command,
+		Entrypoint:   config.Entrypoint,
 		Hostname:     config.HostName,
 		Env:          config.Env,
 		Labels:       config.Labels,
+		WorkingDir:   config.WorkingDir,
+		Healthcheck:  config.HealthCheck,
 		OpenStdin:    true,
 		AttachStdin:  true,
 		AttachStdout: true,
@@ -249,7 +317,7 @@ func (d *Client) ContainerRun(config *ContainerConfig, randomPorts, localUser bo
 
 		currentUser, err = user.Current()
 		if err != nil {
-			return containerID, err
+			return "", err
 		}
 
 		containerConfig.User = fmt.Sprintf("%s:%s", currentUser.Uid, currentUser.Gid)
@@ -260,90 +328,28 @@ func (d *Client) ContainerRun(config *ContainerConfig, randomPorts, localUser bo
 		return "", err
 	}
 
-	err = d.apiClient.ContainerStart(context.Background(), resp.ID, container.StartOptions{})
-	if err != nil {
-		return "", err
-	}
-
 	return resp.ID, nil
 }
 
-func (d *Client) showInteractiveTerminal(containerID string) error {
-	waiter, err := d.apiClient.ContainerAttach(context.Background(), containerID, container.AttachOptions{
-		Stderr: true,
-		Stdout: true,
-		Stdin:  true,
-		Stream: true,
-	})
-	if err != nil {
-		return err
+func (d *Client) ContainerRunAndClean(config *ContainerConfig, interactive bool) (statusCode int64, body string, err error) {
+	if interactive {
+		statusCode, err = d.containerRunInteractive(config)
+		return statusCode, body, err
 	}
 
-	errs, _ := errgroup.WithContext(context.Background())
-
-	errs.Go(func() error {
-		_, err := io.Copy(os.Stdout, waiter.Reader)
-		return err
-	})
-
-	errs.Go(func() error {
-		_, err := io.Copy(os.Stderr, waiter.Reader)
-		return err
-	})
-
-	inOut := make(chan []byte)
-
-	go func() {
-		scanner := bufio.NewScanner(os.Stdin)
-		for scanner.Scan() {
-			inOut <- []byte(scanner.Text())
-		}
-	}()
-
-	// Write to docker container
-	go func(w io.WriteCloser) {
-		for {
-			data, ok := <-inOut
-			if !ok {
-				fmt.Println("!ok")
-				w.Close()
-				return
-			}
-
-			_, err := w.Write(append(data, '\n'))
-			if err != nil {
-				panic(err)
-			}
-		}
-	}(waiter.Conn)
-
-	return nil
-}
-
-func (d *Client) ContainerRunAndClean(config *ContainerConfig, interactive bool) (statusCode int64, body string, err error) {
 	// Start the container
 	id, err := d.ContainerRun(config, false, true)
 	if err != nil {
 		return statusCode, body, err
 	}
 
-	if interactive {
-		err = d.showInteractiveTerminal(id)
-		if err != nil {
-			return statusCode, body, err
-		}
-	}
-
 	// Wait for it to finish
 	statusCode, err = d.containerWait(id)
 	if err != nil {
 		return statusCode, body, err
 	}
 
-	// Get the output if we're not running interactively
-	if !interactive {
-		body, _ = d.containerLog(id)
-	}
+	body, _ = d.containerLog(id)
 
 	err = d.apiClient.ContainerRemove(context.Background(), id, container.RemoveOptions{})
 	return statusCode, body, err
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+	"sync"
 
 	"github.com/ChrisWiegman/kana/internal/console"
 
@@ -28,6 +29,8 @@ type Client struct {
 	apiClient       APIClient
 	imageUpdateData *koanf.Koanf
 	checkedImages   []string
+	imageMutex      sync.Mutex
+	progress        *progressDisplay
 }
 
 type Context struct {
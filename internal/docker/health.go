@@ -0,0 +1,91 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// healthLogLines is the number of log lines shown when a container doesn't become healthy.
+const healthLogLines = 20
+
+var healthPollInterval = 500 * time.Millisecond
+
+// WaitHealthy Waits until the given container reports it is healthy, failing if it becomes unhealthy, exits or the timeout passes.
+// Containers without a health check are considered healthy as soon as they are running.
+func (d *Client) WaitHealthy(containerName string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		containerInfo, err := d.apiClient.ContainerInspect(context.Background(), containerName)
+		if err != nil {
+			return err
+		}
+
+		state := containerInfo.State
+		if state == nil {
+			return fmt.Errorf("unable to read the state of %s", containerName)
+		}
+
+		if !state.Running {
+			return d.healthError(containerName, fmt.Sprintf("stopped with exit code %d", state.ExitCode), nil)
+		}
+
+		if state.Health == nil {
+			return nil
+		}
+
+		switch state.Health.Status {
+		case container.Healthy:
+			return nil
+		case container.Unhealthy:
+			return d.healthError(containerName, "is unhealthy", state.Health)
+		}
+
+		if time.Now().After(deadline) {
+			return d.healthError(containerName, fmt.Sprintf("did not become healthy within %s", timeout), state.Health)
+		}
+
+		time.Sleep(healthPollInterval)
+	}
+}
+
+// ContainerHealth Returns the health of the given container, or "none" if it doesn't have a health check.
+func (d *Client) ContainerHealth(containerName string) (string, error) {
+	containerInfo, err := d.apiClient.ContainerInspect(context.Background(), containerName)
+	if err != nil {
+		return "", err
+	}
+
+	if containerInfo.State == nil || containerInfo.State.Health == nil {
+		return container.NoHealthcheck, nil
+	}
+
+	return containerInfo.State.Health.Status, nil
+}
+
+// healthError Builds an error naming the container, with the output of its last health check and its last log lines.
+func (d *Client) healthError(containerName, reason string, health *container.Health) error {
+	message := fmt.Sprintf("%s %s", containerName, reason)
+
+	if health != nil && len(health.Log) > 0 {
+		lastCheck := strings.TrimSpace(health.Log[len(health.Log)-1].Output)
+
+		if lastCheck != "" {
+			message = fmt.Sprintf("%s. The last health check returned: %s", message, lastCheck)
+		}
+	}
+
+	var logs bytes.Buffer
+
+	err := d.ContainerLogs(containerName, LogOptions{Tail: fmt.Sprint(healthLogLines)}, &logs)
+	if err == nil && strings.TrimSpace(logs.String()) != "" {
+		message = fmt.Sprintf("%s\n\nThe last lines of its log were:\n%s", message, strings.TrimRight(logs.String(), "\n"))
+	}
+
+	return fmt.Errorf("%s", message)
+}
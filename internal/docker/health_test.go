@@ -0,0 +1,143 @@
+package docker
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/docker/mocks"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWaitHealthy(t *testing.T) {
+	healthPollInterval = time.Millisecond
+
+	var tests = []struct {
+		name          string
+		states        []*container.State
+		expectedError string
+	}{
+		{
+			"Test a healthy container",
+			[]*container.State{
+				{Running: true, Health: &container.Health{Status: container.Healthy}},
+			},
+			""},
+		{
+			"Test a container that becomes healthy",
+			[]*container.State{
+				{Running: true, Health: &container.Health{Status: container.Starting}},
+				{Running: true, Health: &container.Health{Status: container.Starting}},
+				{Running: true, Health: &container.Health{Status: container.Healthy}},
+			},
+			""},
+		{
+			"Test a container without a health check",
+			[]*container.State{
+				{Running: true},
+			},
+			""},
+		{
+			"Test an unhealthy container",
+			[]*container.State{
+				{Running: true, Health: &container.Health{
+					Status: container.Unhealthy,
+					Log:    []*container.HealthcheckResult{{ExitCode: 1, Output: "connection refused"}},
+				}},
+			},
+			"kana-test-database is unhealthy. The last health check returned: connection refused\n\n" +
+				"The last lines of its log were:\nstarting server"},
+		{
+			"Test a container that exits",
+			[]*container.State{
+				{Running: false, ExitCode: 1},
+			},
+			"kana-test-database stopped with exit code 1\n\nThe last lines of its log were:\nstarting server"},
+	}
+
+	for _, test := range tests {
+		apiClient := new(mocks.APIClient)
+
+		for _, state := range test.states {
+			apiClient.On("ContainerInspect", context.Background(), "kana-test-database").Return(container.InspectResponse{
+				ContainerJSONBase: &container.ContainerJSONBase{ID: "1234", State: state},
+				Config:            &container.Config{Tty: true},
+			}, nil).Once()
+		}
+
+		if test.expectedError != "" {
+			apiClient.On("ContainerInspect", context.Background(), "kana-test-database").Return(container.InspectResponse{
+				ContainerJSONBase: &container.ContainerJSONBase{ID: "1234"},
+				Config:            &container.Config{Tty: true},
+			}, nil).Once()
+			apiClient.On("ContainerLogs", context.Background(), "1234", mock.Anything).
+				Return(io.NopCloser(strings.NewReader("starting server\n")), nil).Once()
+		}
+
+		d := &Client{apiClient: apiClient}
+
+		err := d.WaitHealthy("kana-test-database", time.Minute)
+
+		if test.expectedError == "" {
+			assert.NoError(t, err, test.name)
+		} else {
+			assert.EqualError(t, err, test.expectedError, test.name)
+		}
+
+		apiClient.AssertExpectations(t)
+	}
+}
+
+func TestWaitHealthyTimeout(t *testing.T) {
+	healthPollInterval = time.Millisecond
+
+	apiClient := new(mocks.APIClient)
+
+	apiClient.On("ContainerInspect", context.Background(), "kana-test-wordpress").Return(container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			ID:    "1234",
+			State: &container.State{Running: true, Health: &container.Health{Status: container.Starting}},
+		},
+		Config: &container.Config{Tty: true},
+	}, nil)
+	apiClient.On("ContainerLogs", context.Background(), "1234", mock.Anything).
+		Return(io.NopCloser(strings.NewReader("")), nil).Once()
+
+	d := &Client{apiClient: apiClient}
+
+	err := d.WaitHealthy("kana-test-wordpress", 10*time.Millisecond)
+
+	assert.EqualError(t, err, "kana-test-wordpress did not become healthy within 10ms")
+}
+
+func TestContainerHealth(t *testing.T) {
+	var tests = []struct {
+		name           string
+		state          *container.State
+		expectedHealth string
+	}{
+		{"Test a healthy container", &container.State{Running: true, Health: &container.Health{Status: container.Healthy}}, "healthy"},
+		{"Test a container without a health check", &container.State{Running: true}, "none"},
+		{"Test a container without a state", nil, "none"},
+	}
+
+	for _, test := range tests {
+		apiClient := new(mocks.APIClient)
+
+		apiClient.On("ContainerInspect", context.Background(), "kana-test-wordpress").Return(container.InspectResponse{
+			ContainerJSONBase: &container.ContainerJSONBase{ID: "1234", State: test.state},
+		}, nil).Once()
+
+		d := &Client{apiClient: apiClient}
+
+		health, err := d.ContainerHealth("kana-test-wordpress")
+
+		assert.NoError(t, err, test.name)
+		assert.Equal(t, test.expectedHealth, health, test.name)
+	}
+}
@@ -1,6 +1,8 @@
 package docker
 
 import (
+	"archive/tar"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -13,31 +15,68 @@ import (
 
 	"github.com/ChrisWiegman/kana/internal/console"
 
+	"github.com/docker/docker/api/types/build"
 	"github.com/docker/docker/api/types/image"
 	kjson "github.com/knadh/koanf/parsers/json"
 	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/v2"
-	"github.com/moby/moby/pkg/jsonmessage"
-	"github.com/moby/term"
+	"golang.org/x/sync/errgroup"
 )
 
-var displayJSONMessagesStream = jsonmessage.DisplayJSONMessagesStream
+// LocalImagePrefix is used for the images Kana builds itself, which can't be pulled from a registry.
+const LocalImagePrefix = "kana/"
 
-// https://gist.github.com/miguelmota/4980b18d750fb3b1eb571c3e207b1b92
-// https://riptutorial.com/docker/example/31980/image-pulling-with-progress-bars--written-in-go
-func (d *Client) EnsureImage(imageName, appDirectory string, updateDays int64, consoleOutput *console.Console) (err error) {
-	if !strings.Contains(imageName, ":") {
-		imageName = fmt.Sprintf("%s:latest", imageName)
-	}
+// ImageError is returned by EnsureImages when one of the images can't be pulled, naming the image as it was requested.
+type ImageError struct {
+	Image string
+	Err   error
+}
 
-	// Skip more complicated checks if we can
-	for _, checkedImage := range d.checkedImages {
-		if checkedImage == imageName {
-			return nil
+func (e *ImageError) Error() string {
+	return fmt.Sprintf("unable to get the image %s: %s", e.Image, e.Err)
+}
+
+func (e *ImageError) Unwrap() error {
+	return e.Err
+}
+
+// EnsureImage Makes sure the given image is available locally, pulling it if it is missing or out of date.
+func (d *Client) EnsureImage(imageName, appDirectory string, updateDays int64, consoleOutput *console.Console) error {
+	return d.EnsureImages([]string{imageName}, appDirectory, updateDays, consoleOutput)
+}
+
+// EnsureImages Makes sure all the given images are available locally, pulling any that are missing or out of date at the same time.
+// The first pull to fail cancels the others.
+func (d *Client) EnsureImages(imageNames []string, appDirectory string, updateDays int64, consoleOutput *console.Console) error {
+	group, ctx := errgroup.WithContext(context.Background())
+
+	queuedImages := map[string]bool{}
+
+	for _, requestedImage := range imageNames {
+		imageName := requestedImage
+
+		if !strings.Contains(imageName, ":") {
+			imageName = fmt.Sprintf("%s:latest", imageName)
 		}
+
+		// Skip more complicated checks if we can
+		if queuedImages[imageName] || d.isCheckedImage(imageName) {
+			continue
+		}
+
+		queuedImages[imageName] = true
+
+		group.Go(func() error {
+			err := d.maybeUpdateImage(ctx, imageName, updateDays, appDirectory, consoleOutput)
+			if err != nil {
+				return &ImageError{Image: requestedImage, Err: err}
+			}
+
+			return nil
+		})
 	}
 
-	return d.maybeUpdateImage(imageName, updateDays, consoleOutput.JSON, appDirectory)
+	return group.Wait()
 }
 
 func ValidateImage(imageName, imageTag string) error {
@@ -72,8 +111,12 @@ func ValidateImage(imageName, imageTag string) error {
 	return err
 }
 
-func (d *Client) maybeUpdateImage(imageName string, updateDays int64, suppressOutput bool, appDirectory string) error {
-	lastUpdated := d.imageUpdateData.Time(imageName, time.RFC3339)
+// EnsureBuiltImage Builds a local image from the given Dockerfile if it is missing or older than the update interval.
+// Build times are tracked in images.json alongside the pulled images.
+func (d *Client) EnsureBuiltImage(imageName, dockerfile, appDirectory string, updateDays int64, consoleOutput *console.Console) error {
+	if d.isCheckedImage(imageName) {
+		return nil
+	}
 
 	imageList, err := d.apiClient.ImageList(context.Background(), image.ListOptions{})
 	if err != nil {
@@ -81,7 +124,136 @@ func (d *Client) maybeUpdateImage(imageName string, updateDays int64, suppressOu
 	}
 
 	hasImage := false
-	checkForUpdate := false
+
+	for i := range imageList {
+		for _, repoTag := range imageList[i].RepoTags {
+			if repoTag == imageName {
+				hasImage = true
+			}
+		}
+	}
+
+	if hasImage && !d.isImageOutdated(imageName, updateDays) {
+		d.setCheckedImage(imageName)
+		return nil
+	}
+
+	buildContext, err := getBuildContext(dockerfile)
+	if err != nil {
+		return err
+	}
+
+	// Pulling the parent keeps rebuilt images up to date with the image they're built from
+	response, err := d.apiClient.ImageBuild(context.Background(), buildContext, build.ImageBuildOptions{
+		Tags:        []string{imageName},
+		Dockerfile:  "Dockerfile",
+		PullParent:  true,
+		Remove:      true,
+		ForceRemove: true,
+	})
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	err = d.getProgress(consoleOutput).track(imageName, "Building", response.Body)
+	if err != nil {
+		return fmt.Errorf("unable to build the image %s: %s", imageName, err)
+	}
+
+	err = d.setImageUpdate(imageName, time.Now(), appDirectory)
+	if err != nil {
+		return err
+	}
+
+	d.setCheckedImage(imageName)
+
+	return nil
+}
+
+// getBuildContext Returns a tar archive containing only the given Dockerfile to send to the build API.
+func getBuildContext(dockerfile string) (io.Reader, error) {
+	var buffer bytes.Buffer
+
+	tarWriter := tar.NewWriter(&buffer)
+
+	err := tarWriter.WriteHeader(&tar.Header{
+		Name:    "Dockerfile",
+		Mode:    0o644,
+		Size:    int64(len(dockerfile)),
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tarWriter.Write([]byte(dockerfile))
+	if err != nil {
+		return nil, err
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return &buffer, nil
+}
+
+// getProgress Returns the display shared by all the pulls and builds of this client.
+func (d *Client) getProgress(consoleOutput *console.Console) *progressDisplay {
+	d.imageMutex.Lock()
+	defer d.imageMutex.Unlock()
+
+	if d.progress == nil {
+		d.progress = newProgressDisplay(consoleOutput)
+	}
+
+	return d.progress
+}
+
+// isCheckedImage Checks if the image has already been checked, and pulled or built if needed, by this client.
+func (d *Client) isCheckedImage(imageName string) bool {
+	d.imageMutex.Lock()
+	defer d.imageMutex.Unlock()
+
+	for _, checkedImage := range d.checkedImages {
+		if checkedImage == imageName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isImageOutdated Checks if the image was last pulled or built longer ago than the update interval.
+func (d *Client) isImageOutdated(imageName string, updateDays int64) bool {
+	if updateDays <= 0 {
+		return false
+	}
+
+	d.imageMutex.Lock()
+	defer d.imageMutex.Unlock()
+
+	hours := 24 * updateDays
+	lastUpdated := d.imageUpdateData.Time(imageName, time.RFC3339)
+
+	return lastUpdated.Compare(time.Now().Add(time.Duration(-hours)*time.Hour)) == -1
+}
+
+func (d *Client) maybeUpdateImage(
+	ctx context.Context,
+	imageName string,
+	updateDays int64,
+	appDirectory string,
+	consoleOutput *console.Console) error {
+	imageList, err := d.apiClient.ImageList(ctx, image.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	hasImage := false
 
 	// Make sure we've actually downloaded the image
 	for i := range imageList {
@@ -93,45 +265,38 @@ func (d *Client) maybeUpdateImage(imageName string, updateDays int64, suppressOu
 		}
 	}
 
-	// Check the image for updates if needed
-	if updateDays > 0 {
-		hours := 24 * updateDays
-		checkForUpdate = lastUpdated.Compare(time.Now().Add(time.Duration(-hours)*time.Hour)) == -1
+	// Images built by Kana only exist locally so there is nothing to pull
+	if strings.HasPrefix(imageName, LocalImagePrefix) {
+		if !hasImage {
+			return fmt.Errorf("the image %s hasn't been built", imageName)
+		}
+
+		d.setCheckedImage(imageName)
+
+		return nil
 	}
 
 	// Pull the image or a newer image if needed
-	if !hasImage || checkForUpdate {
-		reader, err := d.apiClient.ImagePull(context.Background(), imageName, image.PullOptions{})
+	if !hasImage || d.isImageOutdated(imageName, updateDays) {
+		reader, err := d.apiClient.ImagePull(ctx, imageName, image.PullOptions{})
 		if err != nil {
 			return err
 		}
 
-		defer func() {
-			if err = reader.Close(); err != nil {
-				panic(err)
-			}
-		}()
-
-		out := os.Stdout
+		defer reader.Close()
 
-		// Discard the download information if set to suppress
-		if suppressOutput {
-			out, _ = os.Open(os.DevNull)
+		err = d.getProgress(consoleOutput).track(imageName, "Pulling", reader)
+		if err != nil {
+			return err
 		}
 
 		err = d.setImageUpdate(imageName, time.Now(), appDirectory)
 		if err != nil {
 			return err
 		}
-
-		termFd, isTerm := term.GetFdInfo(os.Stdout)
-
-		d.checkedImages = append(d.checkedImages, imageName)
-
-		return displayJSONMessagesStream(reader, out, termFd, isTerm, nil)
 	}
 
-	d.checkedImages = append(d.checkedImages, imageName)
+	d.setCheckedImage(imageName)
 
 	return nil
 }
@@ -157,7 +322,18 @@ func (d *Client) loadImageUpdateData(appDirectory string) (*koanf.Koanf, error)
 	return imageUpdateData, nil
 }
 
+// setCheckedImage Records that the image is ready so it isn't checked again by this client.
+func (d *Client) setCheckedImage(imageName string) {
+	d.imageMutex.Lock()
+	defer d.imageMutex.Unlock()
+
+	d.checkedImages = append(d.checkedImages, imageName)
+}
+
 func (d *Client) setImageUpdate(imageName string, timeStamp time.Time, appDirectory string) error {
+	d.imageMutex.Lock()
+	defer d.imageMutex.Unlock()
+
 	err := d.imageUpdateData.Set(imageName, timeStamp.Format(time.RFC3339))
 	if err != nil {
 		return err
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	build "github.com/docker/docker/api/types/build"
+
 	common "github.com/docker/docker/api/types/common"
 	container "github.com/docker/docker/api/types/container"
 
@@ -166,6 +168,24 @@ func (_m *APIClient) ContainerExecInspect(ctx context.Context, execID string) (c
 	return r0, r1
 }
 
+// ContainerExecResize provides a mock function with given fields: ctx, execID, options
+func (_m *APIClient) ContainerExecResize(ctx context.Context, execID string, options container.ResizeOptions) error {
+	ret := _m.Called(ctx, execID, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContainerExecResize")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, container.ResizeOptions) error); ok {
+		r0 = rf(ctx, execID, options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ContainerInspect provides a mock function with given fields: ctx, _a1
 func (_m *APIClient) ContainerInspect(ctx context.Context, _a1 string) (container.InspectResponse, error) {
 	ret := _m.Called(ctx, _a1)
@@ -272,6 +292,24 @@ func (_m *APIClient) ContainerRemove(ctx context.Context, _a1 string, options co
 	return r0
 }
 
+// ContainerResize provides a mock function with given fields: ctx, _a1, options
+func (_m *APIClient) ContainerResize(ctx context.Context, _a1 string, options container.ResizeOptions) error {
+	ret := _m.Called(ctx, _a1, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ContainerResize")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, container.ResizeOptions) error); ok {
+		r0 = rf(ctx, _a1, options)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ContainerStart provides a mock function with given fields: ctx, _a1, options
 func (_m *APIClient) ContainerStart(ctx context.Context, _a1 string, options container.StartOptions) error {
 	ret := _m.Called(ctx, _a1, options)
@@ -340,6 +378,34 @@ func (_m *APIClient) ContainerWait(ctx context.Context, _a1 string, condition co
 	return r0, r1
 }
 
+// ImageBuild provides a mock function with given fields: ctx, buildContext, options
+func (_m *APIClient) ImageBuild(ctx context.Context, buildContext io.Reader, options build.ImageBuildOptions) (build.ImageBuildResponse, error) {
+	ret := _m.Called(ctx, buildContext, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImageBuild")
+	}
+
+	var r0 build.ImageBuildResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, build.ImageBuildOptions) (build.ImageBuildResponse, error)); ok {
+		return rf(ctx, buildContext, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, build.ImageBuildOptions) build.ImageBuildResponse); ok {
+		r0 = rf(ctx, buildContext, options)
+	} else {
+		r0 = ret.Get(0).(build.ImageBuildResponse)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.Reader, build.ImageBuildOptions) error); ok {
+		r1 = rf(ctx, buildContext, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ImageList provides a mock function with given fields: ctx, options
 func (_m *APIClient) ImageList(ctx context.Context, options image.ListOptions) ([]image.Summary, error) {
 	ret := _m.Called(ctx, options)
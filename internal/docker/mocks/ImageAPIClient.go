@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	build "github.com/docker/docker/api/types/build"
+
 	context "context"
 
 	image "github.com/docker/docker/api/types/image"
@@ -17,6 +19,34 @@ type ImageAPIClient struct {
 	mock.Mock
 }
 
+// ImageBuild provides a mock function with given fields: ctx, buildContext, options
+func (_m *ImageAPIClient) ImageBuild(ctx context.Context, buildContext io.Reader, options build.ImageBuildOptions) (build.ImageBuildResponse, error) {
+	ret := _m.Called(ctx, buildContext, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImageBuild")
+	}
+
+	var r0 build.ImageBuildResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, build.ImageBuildOptions) (build.ImageBuildResponse, error)); ok {
+		return rf(ctx, buildContext, options)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, io.Reader, build.ImageBuildOptions) build.ImageBuildResponse); ok {
+		r0 = rf(ctx, buildContext, options)
+	} else {
+		r0 = ret.Get(0).(build.ImageBuildResponse)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, io.Reader, build.ImageBuildOptions) error); ok {
+		r1 = rf(ctx, buildContext, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ImageList provides a mock function with given fields: ctx, options
 func (_m *ImageAPIClient) ImageList(ctx context.Context, options image.ListOptions) ([]image.Summary, error) {
 	ret := _m.Called(ctx, options)
@@ -14,6 +14,7 @@ import (
 type ExposedPorts struct {
 	Port     string
 	Protocol string
+	HostIP   string
 }
 
 type portConfig struct {
@@ -122,6 +123,7 @@ func getNetworkConfig(ports []ExposedPorts, randomPorts bool) (portConfig, error
 
 		portBindings[portName] = []nat.PortBinding{
 			{
+				HostIP:   port.HostIP,
 				HostPort: hostPort,
 			},
 		}
@@ -0,0 +1,253 @@
+package docker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+
+	"github.com/docker/go-units"
+	"github.com/moby/moby/pkg/jsonmessage"
+	"github.com/moby/term"
+)
+
+// progressRefresh limits how often the progress display is redrawn as the many messages from Docker arrive.
+const progressRefresh = 100 * time.Millisecond
+
+// progressDisplay combines the progress of image pulls and builds running at the same time into one line per image.
+type progressDisplay struct {
+	mutex      sync.Mutex
+	out        io.Writer
+	isTerminal bool
+	tasks      []*progressTask
+	lines      int
+	lastDrawn  time.Time
+}
+
+// progressTask is the progress of a single image.
+type progressTask struct {
+	name   string
+	action string
+	status string
+	done   bool
+	layers map[string]*layerProgress
+}
+
+// layerProgress is the progress of a single layer of an image.
+type layerProgress struct {
+	status         string
+	current, total int64
+}
+
+func newProgressDisplay(consoleOutput *console.Console) *progressDisplay {
+	_, isTerminal := term.GetFdInfo(os.Stdout)
+
+	display := &progressDisplay{
+		out:        os.Stdout,
+		isTerminal: isTerminal,
+	}
+
+	// Discard the progress if set to suppress
+	if consoleOutput.JSON {
+		display.out = io.Discard
+	}
+
+	return display
+}
+
+// track Reads the JSON messages Docker returns for a pull or build, showing their progress, until the stream ends or reports an error.
+func (p *progressDisplay) track(name, action string, reader io.Reader) error {
+	task := p.start(name, action)
+
+	decoder := json.NewDecoder(reader)
+
+	for {
+		var message jsonmessage.JSONMessage
+
+		err := decoder.Decode(&message)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err == nil && message.Error != nil {
+			err = message.Error
+		}
+
+		if err != nil {
+			p.finish(task, "Failed")
+			return err
+		}
+
+		p.update(task, &message)
+	}
+
+	p.finish(task, "Done")
+
+	return nil
+}
+
+// start Adds a line for the given image to the display.
+func (p *progressDisplay) start(name, action string) *progressTask {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	// Start a new block of lines once everything in the last one has finished
+	if p.allDone() {
+		p.tasks = []*progressTask{}
+		p.lines = 0
+	}
+
+	task := &progressTask{
+		name:   name,
+		action: action,
+		status: "Waiting",
+		layers: map[string]*layerProgress{},
+	}
+
+	p.tasks = append(p.tasks, task)
+
+	if !p.isTerminal {
+		fmt.Fprintf(p.out, "%s %s\n", action, name)
+		return task
+	}
+
+	p.draw(true)
+
+	return task
+}
+
+// update Records a message from Docker against the image it belongs to.
+func (p *progressDisplay) update(task *progressTask, message *jsonmessage.JSONMessage) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	switch {
+	case message.Stream != "":
+		// Builds report the step they're on which is more useful than the output of each step
+		if step := strings.TrimSpace(message.Stream); strings.HasPrefix(step, "Step ") {
+			step, _, _ = strings.Cut(step, " :")
+			task.status = step
+		}
+	case message.ID != "" && isLayerStatus(message.Status):
+		layer, ok := task.layers[message.ID]
+		if !ok {
+			layer = &layerProgress{}
+			task.layers[message.ID] = layer
+		}
+
+		layer.status = message.Status
+
+		if message.Status == "Downloading" && message.Progress != nil {
+			layer.current = message.Progress.Current
+			layer.total = message.Progress.Total
+		}
+
+		if message.Status == "Download complete" || message.Status == "Pull complete" {
+			layer.current = layer.total
+		}
+	}
+
+	if p.isTerminal {
+		p.draw(false)
+	}
+}
+
+// finish Marks the image as done, or failed, and redraws the display.
+func (p *progressDisplay) finish(task *progressTask, status string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	task.done = true
+	task.status = status
+
+	if !p.isTerminal {
+		fmt.Fprintf(p.out, "%s %s: %s\n", task.action, task.name, status)
+		return
+	}
+
+	p.draw(true)
+}
+
+// allDone Checks if every image in the display has finished.
+func (p *progressDisplay) allDone() bool {
+	for _, task := range p.tasks {
+		if !task.done {
+			return false
+		}
+	}
+
+	return true
+}
+
+// draw Rewrites the lines for every image in place. The caller must hold the lock.
+func (p *progressDisplay) draw(force bool) {
+	if !force && time.Since(p.lastDrawn) < progressRefresh {
+		return
+	}
+
+	p.lastDrawn = time.Now()
+
+	if p.lines > 0 {
+		fmt.Fprintf(p.out, "\033[%dA", p.lines)
+	}
+
+	for _, task := range p.tasks {
+		fmt.Fprintf(p.out, "\r\033[K%s %s: %s\n", task.action, task.name, task.summary())
+	}
+
+	p.lines = len(p.tasks)
+}
+
+// summary Describes the progress of the image across all of its layers.
+func (t *progressTask) summary() string {
+	if t.done || len(t.layers) == 0 {
+		return t.status
+	}
+
+	var complete int
+	var current, total int64
+
+	status := "Extracting"
+
+	for _, layer := range t.layers {
+		switch layer.status {
+		case "Pull complete", "Already exists":
+			complete++
+		case "Downloading", "Pulling fs layer", "Waiting":
+			status = "Downloading"
+		}
+
+		current += layer.current
+		total += layer.total
+	}
+
+	summary := fmt.Sprintf("%s %d/%d layers", status, complete, len(t.layers))
+
+	if total > 0 {
+		summary = fmt.Sprintf("%s (%s/%s)", summary, units.HumanSize(float64(current)), units.HumanSize(float64(total)))
+	}
+
+	// Builds pull their parent image before running their steps
+	if t.status != "Waiting" {
+		summary = fmt.Sprintf("%s, %s", t.status, summary)
+	}
+
+	return summary
+}
+
+// isLayerStatus Checks if a status from Docker is about a single layer rather than the whole image.
+func isLayerStatus(status string) bool {
+	switch status {
+	case "Pulling fs layer", "Waiting", "Downloading", "Verifying Checksum",
+		"Download complete", "Extracting", "Pull complete", "Already exists":
+		return true
+	}
+
+	return false
+}
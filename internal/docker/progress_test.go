@@ -0,0 +1,67 @@
+package docker
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressTrack(t *testing.T) {
+	var tests = []struct {
+		name           string
+		messages       string
+		expectedError  string
+		expectedOutput string
+	}{
+		{
+			"Test a successful pull",
+			`{"status":"Pulling from library/redis","id":"latest"}
+{"status":"Pulling fs layer","id":"a1"}
+{"status":"Downloading","id":"a1","progressDetail":{"current":50,"total":100}}
+{"status":"Pull complete","id":"a1"}
+{"status":"Status: Downloaded newer image for redis:latest"}`,
+			"",
+			"Pulling redis:latest\nPulling redis:latest: Done\n"},
+		{
+			"Test a failed pull",
+			`{"status":"Pulling fs layer","id":"a1"}
+{"errorDetail":{"message":"unexpected EOF"},"error":"unexpected EOF"}`,
+			"unexpected EOF",
+			"Pulling redis:latest\nPulling redis:latest: Failed\n"},
+	}
+
+	for _, test := range tests {
+		var out bytes.Buffer
+
+		display := &progressDisplay{out: &out}
+
+		err := display.track("redis:latest", "Pulling", strings.NewReader(test.messages))
+
+		if test.expectedError == "" {
+			assert.NoError(t, err, test.name)
+		} else {
+			assert.EqualError(t, err, test.expectedError, test.name)
+		}
+
+		assert.Equal(t, test.expectedOutput, out.String(), test.name)
+	}
+}
+
+func TestProgressSummary(t *testing.T) {
+	task := &progressTask{
+		status: "Step 2/5",
+		layers: map[string]*layerProgress{
+			"a1": {status: "Pull complete", current: 1000, total: 1000},
+			"b2": {status: "Downloading", current: 500, total: 1000},
+		},
+	}
+
+	assert.Equal(t, "Step 2/5, Downloading 1/2 layers (1.5kB/2kB)", task.summary())
+
+	task.done = true
+	task.status = "Done"
+
+	assert.Equal(t, "Done", task.summary())
+}
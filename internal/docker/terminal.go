@@ -0,0 +1,344 @@
+package docker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/moby/term"
+	"golang.org/x/sys/unix"
+)
+
+// How often a waiting read of the user's input checks whether the session has ended.
+const inputPollInterval = 100 * time.Millisecond
+
+// detachKeys are ctrl-p followed by ctrl-q, the same keys Docker uses to detach from a container.
+var detachKeys = []byte{16, 17}
+
+// errDetached is returned by stream when the user detaches with the detach keys.
+var errDetached = errors.New("detached from the container")
+
+// terminal holds the state of the user's terminal while it is attached to a container.
+type terminal struct {
+	inFd, outFd uintptr
+	isTerminal  bool
+	state       *term.State
+	in          io.Reader
+	out, errOut io.Writer
+	getSize     func() (*term.Winsize, error)
+}
+
+// inputReader reads the user's input only once some is waiting, so a session that has ended doesn't take the next
+// keystroke meant for the user's shell.
+type inputReader struct {
+	fd   int
+	done chan struct{}
+}
+
+func newTerminal() *terminal {
+	inFd, isTerminal := term.GetFdInfo(os.Stdin)
+	outFd, _ := term.GetFdInfo(os.Stdout)
+
+	return &terminal{
+		inFd:       inFd,
+		outFd:      outFd,
+		isTerminal: isTerminal,
+		out:        os.Stdout,
+		errOut:     os.Stderr,
+		getSize: func() (*term.Winsize, error) {
+			return term.GetWinsize(outFd)
+		},
+	}
+}
+
+// ContainerExecInteractive Runs a command in a running container attached to the user's terminal and returns its exit code.
+func (d *Client) ContainerExecInteractive(containerName string, rootUser bool, command []string) (int, error) {
+	containerID, isRunning := d.containerIsRunning(containerName)
+	if !isRunning {
+		return 1, fmt.Errorf("the container %s is not running", containerName)
+	}
+
+	userTerminal := newTerminal()
+
+	execConfig := container.ExecOptions{
+		Tty:          userTerminal.isTerminal,
+		AttachStdin:  true,
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          command,
+	}
+
+	if rootUser {
+		execConfig.User = "root"
+	}
+
+	execResponse, err := d.apiClient.ContainerExecCreate(context.Background(), containerID, execConfig)
+	if err != nil {
+		return 1, err
+	}
+
+	attachResponse, err := d.apiClient.ContainerExecAttach(
+		context.Background(),
+		execResponse.ID,
+		container.ExecStartOptions{Tty: userTerminal.isTerminal})
+	if err != nil {
+		return 1, err
+	}
+
+	defer attachResponse.Close()
+
+	err = userTerminal.makeRaw()
+	if err != nil {
+		return 1, err
+	}
+
+	defer userTerminal.restore()
+
+	stopResizing := userTerminal.monitorSize(func(height, width uint) error {
+		return d.apiClient.ContainerExecResize(
+			context.Background(),
+			execResponse.ID,
+			container.ResizeOptions{Height: height, Width: width})
+	})
+
+	defer stopResizing()
+
+	err = userTerminal.stream(attachResponse)
+	if errors.Is(err, errDetached) {
+		// The command keeps running in the container, as with docker exec, so there is no exit code yet
+		return 0, nil
+	}
+
+	if err != nil {
+		return 1, err
+	}
+
+	inspectResponse, err := d.apiClient.ContainerExecInspect(context.Background(), execResponse.ID)
+	if err != nil {
+		return 1, err
+	}
+
+	return inspectResponse.ExitCode, nil
+}
+
+// containerRunInteractive Creates and starts a container attached to the user's terminal, waiting for it to exit.
+func (d *Client) containerRunInteractive(config *ContainerConfig) (int64, error) {
+	userTerminal := newTerminal()
+
+	containerID, err := d.containerCreate(config, false, true, userTerminal.isTerminal)
+	if err != nil {
+		return 1, err
+	}
+
+	// Remove the container however we leave so failed runs don't pile up
+	defer d.apiClient.ContainerRemove(context.Background(), containerID, container.RemoveOptions{Force: true}) //nolint:errcheck
+
+	// Attach before starting so we don't miss any output
+	attachResponse, err := d.apiClient.ContainerAttach(context.Background(), containerID, container.AttachOptions{
+		Stream: true,
+		Stdin:  true,
+		Stdout: true,
+		Stderr: true,
+	})
+	if err != nil {
+		return 1, err
+	}
+
+	defer attachResponse.Close()
+
+	err = d.apiClient.ContainerStart(context.Background(), containerID, container.StartOptions{})
+	if err != nil {
+		return 1, err
+	}
+
+	err = userTerminal.makeRaw()
+	if err != nil {
+		return 1, err
+	}
+
+	defer userTerminal.restore()
+
+	stopResizing := userTerminal.monitorSize(func(height, width uint) error {
+		return d.apiClient.ContainerResize(
+			context.Background(),
+			containerID,
+			container.ResizeOptions{Height: height, Width: width})
+	})
+
+	defer stopResizing()
+
+	err = userTerminal.stream(attachResponse)
+	if errors.Is(err, errDetached) {
+		// One-off containers are removed when we leave so detaching ends the command
+		return 0, nil
+	}
+
+	if err != nil {
+		return 1, err
+	}
+
+	return d.containerWait(containerID)
+}
+
+// makeRaw Puts the user's terminal into raw mode so every keystroke goes straight to the container.
+func (t *terminal) makeRaw() error {
+	if !t.isTerminal {
+		return nil
+	}
+
+	state, err := term.SetRawTerminal(t.inFd)
+	if err != nil {
+		return err
+	}
+
+	t.state = state
+
+	return nil
+}
+
+// monitorSize Keeps the container's TTY the same size as the user's terminal until the returned function is called.
+func (t *terminal) monitorSize(resize func(height, width uint) error) func() {
+	if !t.isTerminal {
+		return func() {}
+	}
+
+	resizeTTY := func() {
+		size, err := t.getSize()
+		if err != nil || size.Height == 0 || size.Width == 0 {
+			return
+		}
+
+		// A failed resize only affects the layout so there's nothing to report
+		_ = resize(uint(size.Height), uint(size.Width))
+	}
+
+	resizeTTY()
+
+	resizeSignal := make(chan os.Signal, 1)
+	done := make(chan struct{})
+
+	signal.Notify(resizeSignal, syscall.SIGWINCH)
+
+	go func() {
+		for {
+			select {
+			case <-resizeSignal:
+				resizeTTY()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(resizeSignal)
+		close(done)
+	}
+}
+
+// restore Returns the user's terminal to the state it was in before it was attached.
+func (t *terminal) restore() {
+	if t.state == nil {
+		return
+	}
+
+	_ = term.RestoreTerminal(t.inFd, t.state)
+	t.state = nil
+}
+
+// stream Copies the user's input to the container and the container's output back until the output ends or the user
+// detaches with ctrl-p ctrl-q.
+func (t *terminal) stream(attachResponse types.HijackedResponse) error {
+	input := t.in
+	done := make(chan struct{})
+	detached := make(chan struct{})
+
+	// Stop reading the user's input when we return so it doesn't outlive the session
+	defer close(done)
+
+	if input == nil {
+		input = &inputReader{fd: int(t.inFd), done: done}
+	}
+
+	go func() {
+		_, err := io.Copy(attachResponse.Conn, term.NewEscapeProxy(input, detachKeys))
+
+		var escapeErr term.EscapeError
+
+		switch {
+		case errors.As(err, &escapeErr):
+			close(detached)
+
+			// Closing the connection ends the copy of the container's output
+			attachResponse.Close()
+		case err == nil:
+			// Let the container know there is no more input
+			_ = attachResponse.CloseWrite()
+		}
+	}()
+
+	var err error
+
+	// Without a TTY stdout and stderr are multiplexed on the same stream
+	if t.isTerminal {
+		_, err = io.Copy(t.out, attachResponse.Reader)
+	} else {
+		_, err = stdcopy.StdCopy(t.out, t.errOut, attachResponse.Reader)
+	}
+
+	select {
+	case <-detached:
+		return errDetached
+	default:
+	}
+
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	return nil
+}
+
+// Read Waits for the user's input, returning io.EOF once the session has ended.
+func (r *inputReader) Read(p []byte) (int, error) {
+	for {
+		select {
+		case <-r.done:
+			return 0, io.EOF
+		default:
+		}
+
+		readFds := &unix.FdSet{}
+		readFds.Set(r.fd)
+
+		timeout := unix.NsecToTimeval(inputPollInterval.Nanoseconds())
+
+		ready, err := unix.Select(r.fd+1, readFds, nil, nil, &timeout)
+		if errors.Is(err, unix.EINTR) || (err == nil && ready == 0) {
+			continue
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		n, err := unix.Read(r.fd, p)
+		if err != nil {
+			return 0, err
+		}
+
+		if n == 0 {
+			return 0, io.EOF
+		}
+
+		return n, nil
+	}
+}
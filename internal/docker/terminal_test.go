@@ -0,0 +1,128 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/moby/term"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStream(t *testing.T) {
+	var multiplexed bytes.Buffer
+
+	_, err := stdcopy.NewStdWriter(&multiplexed, stdcopy.Stdout).Write([]byte("installed\n"))
+	assert.NoError(t, err)
+
+	_, err = stdcopy.NewStdWriter(&multiplexed, stdcopy.Stderr).Write([]byte("warning\n"))
+	assert.NoError(t, err)
+
+	conn, containerConn := net.Pipe()
+	defer conn.Close()
+	defer containerConn.Close()
+
+	go io.Copy(io.Discard, containerConn) //nolint:errcheck
+
+	var stdout, stderr bytes.Buffer
+
+	userTerminal := &terminal{
+		in:     strings.NewReader("y\n"),
+		out:    &stdout,
+		errOut: &stderr,
+	}
+
+	err = userTerminal.stream(types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(&multiplexed)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "installed\n", stdout.String())
+	assert.Equal(t, "warning\n", stderr.String())
+}
+
+func TestStreamDetach(t *testing.T) {
+	conn, containerConn := net.Pipe()
+	defer containerConn.Close()
+
+	received := make(chan string)
+
+	go func() {
+		input, _ := io.ReadAll(containerConn)
+		received <- string(input)
+	}()
+
+	var stdout bytes.Buffer
+
+	userTerminal := &terminal{
+		isTerminal: true,
+		in:         strings.NewReader("ls\x10\x11"),
+		out:        &stdout,
+	}
+
+	err := userTerminal.stream(types.HijackedResponse{Conn: conn, Reader: bufio.NewReader(conn)})
+
+	assert.ErrorIs(t, err, errDetached)
+	assert.Equal(t, "ls", <-received)
+}
+
+func TestMonitorSize(t *testing.T) {
+	var tests = []struct {
+		name        string
+		isTerminal  bool
+		resizeCalls int
+	}{
+		{
+			name:        "Terminal",
+			isTerminal:  true,
+			resizeCalls: 2,
+		},
+		{
+			name:        "Not a terminal",
+			isTerminal:  false,
+			resizeCalls: 0,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resized := make(chan [2]uint, 2)
+
+			userTerminal := &terminal{
+				isTerminal: test.isTerminal,
+				getSize: func() (*term.Winsize, error) {
+					return &term.Winsize{Height: 24, Width: 80}, nil
+				},
+			}
+
+			stopResizing := userTerminal.monitorSize(func(height, width uint) error {
+				resized <- [2]uint{height, width}
+				return nil
+			})
+
+			err := syscall.Kill(syscall.Getpid(), syscall.SIGWINCH)
+			assert.NoError(t, err)
+
+			calls := 0
+
+			for calls < test.resizeCalls {
+				select {
+				case size := <-resized:
+					assert.Equal(t, [2]uint{24, 80}, size)
+					calls++
+				case <-time.After(time.Second):
+					t.Fatalf("got %d resizes, want %d", calls, test.resizeCalls)
+				}
+			}
+
+			stopResizing()
+
+			assert.Empty(t, resized)
+		})
+	}
+}
@@ -1,8 +1,10 @@
 package helpers
 
 import (
+	"archive/tar"
 	"archive/zip"
 	"bufio"
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
@@ -14,6 +16,12 @@ import (
 	"strings"
 )
 
+// ArchiveEntry maps a file or directory on disk to its path within an archive.
+type ArchiveEntry struct {
+	Name string
+	Path string
+}
+
 // ArrayContains Searches an array of strings for a given string and returns true/false as appropriate.
 func ArrayContains(array []string, name string) bool {
 	for _, value := range array {
@@ -118,6 +126,54 @@ func CopyFile(sourceFile, destinationFile string) error {
 	return err
 }
 
+// GzipFile compresses the source file with gzip and saves it to the destination file.
+func GzipFile(sourceFile, destinationFile string) error {
+	source, err := os.Open(sourceFile)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	destination, err := os.Create(destinationFile)
+	if err != nil {
+		return err
+	}
+	defer destination.Close()
+
+	gzipWriter := gzip.NewWriter(destination)
+
+	_, err = io.Copy(gzipWriter, source)
+	if err != nil {
+		return err
+	}
+
+	return gzipWriter.Close()
+}
+
+// GunzipFile decompresses a gzip compressed source file and saves it to the destination file.
+func GunzipFile(sourceFile, destinationFile string) error {
+	source, err := os.Open(sourceFile)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	gzipReader, err := gzip.NewReader(source)
+	if err != nil {
+		return err
+	}
+	defer gzipReader.Close()
+
+	destination, err := os.Create(destinationFile)
+	if err != nil {
+		return err
+	}
+	defer destination.Close()
+
+	_, err = io.Copy(destination, gzipReader) //nolint:gosec
+	return err
+}
+
 // DownloadFile downloads a file from a given URL and saves it to the destination path.
 func DownloadFile(downloadURL, destinationPath string) (string, error) {
 	// Build fileName from fullPath
@@ -214,3 +270,206 @@ func UnZipFile(sourceFile, destinationPath string) error {
 
 	return nil
 }
+
+// CreateArchive writes a gzip compressed tar archive containing the given files and directories.
+func CreateArchive(archiveFile string, entries []ArchiveEntry) error {
+	file, err := os.Create(archiveFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for _, entry := range entries {
+		err = filepath.WalkDir(entry.Path, func(filePath string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+
+			relativePath, err := filepath.Rel(entry.Path, filePath)
+			if err != nil {
+				return err
+			}
+
+			return addFileToArchive(tarWriter, filePath, filepath.ToSlash(filepath.Join(entry.Name, relativePath)))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	return gzipWriter.Close()
+}
+
+// ExtractArchive extracts a gzip compressed tar archive to a given destination path.
+func ExtractArchive(archiveFile, destinationPath string) error {
+	file, err := os.Open(archiveFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		filePath := filepath.Join(destinationPath, filepath.Clean(header.Name))
+
+		// Don't allow entries to escape the destination
+		if !strings.HasPrefix(filePath, filepath.Clean(destinationPath)+string(os.PathSeparator)) {
+			return fmt.Errorf("the archive contains an invalid path: %s", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(filePath, os.FileMode(header.Mode).Perm()|0700) //nolint:mnd
+			if err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			err = extractArchiveFile(tarReader, filePath, os.FileMode(header.Mode).Perm())
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// CopyDirectory recursively copies the contents of a directory to the destination.
+func CopyDirectory(sourceDirectory, destinationDirectory string) error {
+	return filepath.WalkDir(sourceDirectory, func(filePath string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(sourceDirectory, filePath)
+		if err != nil {
+			return err
+		}
+
+		destinationPath := filepath.Join(destinationDirectory, relativePath)
+
+		if d.IsDir() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+
+			return os.MkdirAll(destinationPath, info.Mode().Perm())
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		return CopyFile(filePath, destinationPath)
+	})
+}
+
+// DirectorySize Returns the total size of the regular files in a directory, skipping anything that can't be read.
+func DirectorySize(directory string) (int64, error) {
+	var size int64
+
+	_, err := os.Stat(directory)
+	if err != nil {
+		return 0, err
+	}
+
+	err = filepath.WalkDir(directory, func(filePath string, d os.DirEntry, err error) error {
+		// Containers can own files, such as the database's, that the current user can't read
+		if err != nil {
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+
+		size += info.Size()
+
+		return nil
+	})
+
+	return size, err
+}
+
+func addFileToArchive(tarWriter *tar.Writer, filePath, name string) error {
+	info, err := os.Lstat(filePath)
+	if err != nil {
+		return err
+	}
+
+	// Only regular files and directories are archived
+	if !info.IsDir() && !info.Mode().IsRegular() {
+		return nil
+	}
+
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+
+	header.Name = name
+
+	err = tarWriter.WriteHeader(header)
+	if err != nil || info.IsDir() {
+		return err
+	}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(tarWriter, file)
+
+	return err
+}
+
+func extractArchiveFile(reader io.Reader, filePath string, mode os.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(file, reader) //nolint:gosec
+
+	return err
+}
@@ -220,6 +220,45 @@ func TestCopyFile(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+func TestGzipFile(t *testing.T) {
+	// Create a temporary directory for testing
+	tempDir, err := os.MkdirTemp("", "testdir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	sourceFile := filepath.Join(tempDir, "source.sql")
+	compressedFile := filepath.Join(tempDir, "source.sql.gz")
+	restoredFile := filepath.Join(tempDir, "restored.sql")
+	data := "CREATE TABLE test (id int);\n"
+
+	err = os.WriteFile(sourceFile, []byte(data), os.ModePerm) //nolint: gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = GzipFile(sourceFile, compressedFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = GunzipFile(compressedFile, restoredFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restoredData, err := os.ReadFile(restoredFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, data, string(restoredData), "Expected the restored file to match the original")
+
+	err = GunzipFile(sourceFile, restoredFile)
+	assert.Error(t, err, "Expected an error when decompressing a file that isn't compressed")
+}
+
 func TestDownloadFile(t *testing.T) {
 	destinationPath, err := os.Getwd()
 	if err != nil {
@@ -306,6 +345,68 @@ func TestUnZipFile(t *testing.T) {
 	assert.True(t, exists, "Expected extracted file2 to exist")
 }
 
+func TestCreateAndExtractArchive(t *testing.T) {
+	// Create a temporary directory for testing
+	tempDir, err := os.MkdirTemp("", "testdir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	sourceDir := filepath.Join(tempDir, "source")
+
+	err = os.MkdirAll(filepath.Join(sourceDir, "plugins", "test"), os.ModePerm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(filepath.Join(sourceDir, "plugins", "test", "test.php"), []byte("<?php"), os.ModePerm) //nolint: gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	singleFile := filepath.Join(tempDir, "database.sql")
+
+	err = os.WriteFile(singleFile, []byte("Test data"), os.ModePerm) //nolint: gosec
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archiveFile := filepath.Join(tempDir, "test.tar.gz")
+
+	err = CreateArchive(archiveFile, []ArchiveEntry{
+		{Name: "wp-content", Path: sourceDir},
+		{Name: "database.sql", Path: singleFile},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	destinationDir := filepath.Join(tempDir, "destination")
+
+	err = ExtractArchive(archiveFile, destinationDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(destinationDir, "wp-content", "plugins", "test", "test.php"))
+	assert.NoError(t, err)
+	assert.Equal(t, "<?php", string(content), "Expected the archived directory to be extracted")
+
+	content, err = os.ReadFile(filepath.Join(destinationDir, "database.sql"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Test data", string(content), "Expected the archived file to be extracted")
+
+	copyDir := filepath.Join(tempDir, "copy")
+
+	err = CopyDirectory(filepath.Join(destinationDir, "wp-content"), copyDir)
+	assert.NoError(t, err)
+
+	exists, err := PathExists(filepath.Join(copyDir, "plugins", "test", "test.php"))
+	assert.NoError(t, err)
+	assert.True(t, exists, "Expected the copied directory to contain the extracted file")
+}
+
 // Helper function to create a temporary zip file for testing.
 func createTempZipFile(zipFile string) error {
 	// Create a new zip file
@@ -373,3 +474,25 @@ func TestArrayContains(t *testing.T) {
 		assert.Equal(t, test.expected, result, test.name)
 	}
 }
+
+func TestDirectorySize(t *testing.T) {
+	directory := t.TempDir()
+
+	err := os.MkdirAll(filepath.Join(directory, "nested"), 0750)
+	assert.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(directory, "a.txt"), []byte("12345"), 0600)
+	assert.NoError(t, err)
+
+	err = os.WriteFile(filepath.Join(directory, "nested", "b.txt"), []byte("1234567890"), 0600)
+	assert.NoError(t, err)
+
+	size, err := DirectorySize(directory)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(15), size)
+
+	_, err = DirectorySize(filepath.Join(directory, "missing"))
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,220 @@
+package settings
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana/pkg/minica"
+)
+
+var errNoCerts = fmt.Errorf("kana hasn't created its certificates yet. Start a site or run 'kana trust-ssl' to create them")
+
+// CertificateInfo describes one of the certificates Kana serves its sites with.
+type CertificateInfo struct {
+	Name        string    `json:"name"`
+	File        string    `json:"file"`
+	Subject     string    `json:"subject"`
+	Issuer      string    `json:"issuer"`
+	DNSNames    []string  `json:"dnsNames"`
+	IPAddresses []string  `json:"ipAddresses"`
+	KeyType     string    `json:"keyType"`
+	IsCA        bool      `json:"isCA"`
+	NotBefore   time.Time `json:"notBefore"`
+	NotAfter    time.Time `json:"notAfter"`
+	SHA256      string    `json:"sha256"`
+}
+
+// GetCertificates Returns the details of the root certificate, the shared *.sites.kana.sh certificate and the
+// certificate of each site.
+func GetCertificates(appDirectory string) ([]CertificateInfo, error) {
+	certificates := []CertificateInfo{}
+	certPath := filepath.Join(appDirectory, "certs")
+
+	if _, err := os.Stat(filepath.Join(certPath, rootCert)); os.IsNotExist(err) {
+		return certificates, errNoCerts
+	}
+
+	certFiles := map[string]string{
+		"root":   rootCert,
+		"shared": siteCert,
+	}
+
+	siteCerts, err := getSiteCertNames(appDirectory)
+	if err != nil {
+		return certificates, err
+	}
+
+	names := []string{"root", "shared"}
+
+	for _, name := range siteCerts {
+		certFiles[name] = filepath.Join("sites", name+".pem")
+		names = append(names, name)
+	}
+
+	for _, name := range names {
+		certificate, err := getCertificateInfo(filepath.Join(certPath, certFiles[name]))
+		if err != nil {
+			return certificates, err
+		}
+
+		certificate.Name = name
+		certificates = append(certificates, certificate)
+	}
+
+	return certificates, nil
+}
+
+// RenewCerts Signs new copies of the shared certificate and every site's certificate with the existing root certificate.
+func RenewCerts(appDirectory string) error {
+	certPath := filepath.Join(appDirectory, "certs")
+
+	_, err := os.Stat(filepath.Join(certPath, rootCert))
+	if err != nil {
+		return errNoCerts
+	}
+
+	certInfos := []minica.CertInfo{{
+		CertDir:  certPath,
+		RootKey:  rootKey,
+		RootCert: rootCert,
+		SiteCert: siteCert,
+		SiteKey:  siteKey,
+	}}
+
+	siteCerts, err := getSiteCertNames(appDirectory)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range siteCerts {
+		certInfos = append(certInfos, minica.CertInfo{
+			CertDir:  certPath,
+			RootKey:  rootKey,
+			RootCert: rootCert,
+			SiteCert: filepath.Join("sites", name+".pem"),
+			SiteKey:  filepath.Join("sites", name+".key"),
+		})
+	}
+
+	for i := range certInfos {
+		err = minica.RenewCert(&certInfos[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RotateCA Replaces the root certificate with a new one and signs every other certificate again with it. The old root
+// certificate should be untrusted first and the new one trusted afterwards.
+func RotateCA(appDirectory string) error {
+	certPath := filepath.Join(appDirectory, "certs")
+
+	for _, file := range []string{rootKey, rootCert} {
+		err := os.Remove(filepath.Join(certPath, file))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	err := os.MkdirAll(certPath, os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return err
+	}
+
+	// The shared certificate is signed by the new root certificate as it is created
+	certInfo := minica.CertInfo{
+		CertDir:    certPath,
+		CertDomain: domain,
+		RootKey:    rootKey,
+		RootCert:   rootCert,
+		SiteCert:   siteCert,
+		SiteKey:    siteKey,
+	}
+
+	_, err = minica.EnsureCert(&certInfo, []string{"*." + domain}, nil)
+	if err != nil {
+		return err
+	}
+
+	return RenewCerts(appDirectory)
+}
+
+// getCertificateInfo Reads the details of a certificate file.
+func getCertificateInfo(certFile string) (CertificateInfo, error) {
+	cert, err := readCertFile(certFile)
+	if err != nil {
+		return CertificateInfo{}, err
+	}
+
+	certificate := CertificateInfo{
+		File:        certFile,
+		Subject:     cert.Subject.String(),
+		Issuer:      cert.Issuer.String(),
+		DNSNames:    cert.DNSNames,
+		IPAddresses: []string{},
+		KeyType:     getKeyType(cert),
+		IsCA:        cert.IsCA,
+		NotBefore:   cert.NotBefore,
+		NotAfter:    cert.NotAfter,
+		SHA256:      getFingerprint(cert),
+	}
+
+	if certificate.DNSNames == nil {
+		certificate.DNSNames = []string{}
+	}
+
+	for _, ipAddress := range cert.IPAddresses {
+		certificate.IPAddresses = append(certificate.IPAddresses, ipAddress.String())
+	}
+
+	return certificate, nil
+}
+
+// readCertFile Parses the first certificate in a PEM file.
+func readCertFile(certFile string) (*x509.Certificate, error) {
+	certContents, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(certContents)
+	if block == nil {
+		return nil, fmt.Errorf("no certificate found in %s", certFile)
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// getKeyType Describes the type and size of a certificate's public key.
+func getKeyType(cert *x509.Certificate) string {
+	switch publicKey := cert.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		return fmt.Sprintf("ECDSA %s", publicKey.Curve.Params().Name)
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d", publicKey.N.BitLen())
+	}
+
+	return cert.PublicKeyAlgorithm.String()
+}
+
+// getFingerprint Returns the SHA-256 fingerprint of a certificate as colon separated hex.
+func getFingerprint(cert *x509.Certificate) string {
+	fingerprint := sha256.Sum256(cert.Raw)
+	hexBytes := make([]string, len(fingerprint))
+
+	for i, fingerprintByte := range fingerprint {
+		hexBytes[i] = fmt.Sprintf("%02X", fingerprintByte)
+	}
+
+	return strings.Join(hexBytes, ":")
+}
@@ -0,0 +1,89 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetCertificates(t *testing.T) {
+	appDirectory := t.TempDir()
+
+	_, err := GetCertificates(appDirectory)
+	assert.Error(t, err)
+
+	_, err = EnsureSSLCerts(appDirectory, false, new(console.Console))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = EnsureSiteCert(appDirectory, "test", []string{"test.sites.kana.sh", "*.test.sites.kana.sh"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certificates, err := GetCertificates(appDirectory)
+	assert.NoError(t, err)
+	assert.Len(t, certificates, 3)
+
+	assert.Equal(t, "root", certificates[0].Name)
+	assert.True(t, certificates[0].IsCA)
+	assert.Contains(t, certificates[0].Subject, "CN=Kana Development CA")
+	assert.Equal(t, "ECDSA P-256", certificates[0].KeyType)
+
+	assert.Equal(t, "shared", certificates[1].Name)
+	assert.Equal(t, []string{"*.sites.kana.sh"}, certificates[1].DNSNames)
+	assert.Empty(t, certificates[1].IPAddresses)
+
+	assert.Equal(t, "test", certificates[2].Name)
+	assert.False(t, certificates[2].IsCA)
+	assert.Equal(t, []string{"test.sites.kana.sh", "*.test.sites.kana.sh"}, certificates[2].DNSNames)
+	assert.Equal(t, []string{"127.0.0.1", "::1"}, certificates[2].IPAddresses)
+	assert.Equal(t, "ECDSA P-256", certificates[2].KeyType)
+	assert.Regexp(t, "^([0-9A-F]{2}:){31}[0-9A-F]{2}$", certificates[2].SHA256)
+}
+
+func TestRenewCertsAndRotateCA(t *testing.T) {
+	appDirectory := t.TempDir()
+	domains := []string{"test.sites.kana.sh", "shop.test"}
+
+	assert.Error(t, RenewCerts(appDirectory))
+
+	_, err := EnsureSSLCerts(appDirectory, false, new(console.Console))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = EnsureSiteCert(appDirectory, "test", domains)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	original, err := GetCertificates(appDirectory)
+	assert.NoError(t, err)
+
+	// Renewing replaces the leaf certificates but keeps the root certificate and the domains
+	assert.NoError(t, RenewCerts(appDirectory))
+
+	renewed, err := GetCertificates(appDirectory)
+	assert.NoError(t, err)
+	assert.Equal(t, original[0].SHA256, renewed[0].SHA256)
+	assert.NotEqual(t, original[1].SHA256, renewed[1].SHA256)
+	assert.NotEqual(t, original[2].SHA256, renewed[2].SHA256)
+	assert.Equal(t, domains, renewed[2].DNSNames)
+
+	assert.NoError(t, RotateCA(appDirectory))
+
+	rotated, err := GetCertificates(appDirectory)
+	assert.NoError(t, err)
+	assert.NotEqual(t, renewed[0].SHA256, rotated[0].SHA256)
+	assert.NotEqual(t, renewed[2].SHA256, rotated[2].SHA256)
+	assert.Equal(t, domains, rotated[2].DNSNames)
+
+	// The site certificate is already signed by the new root certificate
+	changed, err := EnsureSiteCert(appDirectory, "test", domains)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+}
@@ -34,8 +34,15 @@ func ListSettings(settings *Settings, consoleOutput *console.Console) {
 		localOutput := fmt.Sprint(localSettings[settings.settings[i].name])
 
 		if settings.settings[i].settingType == "slice" { //nolint:goconst
-			globalOutput = strings.Join(globalSettings[settings.settings[i].name].([]string), "\n")
-			localOutput = strings.Join(localSettings[settings.settings[i].name].([]string), "\n")
+			globalSlice, _ := globalSettings[settings.settings[i].name].([]string)
+			localSlice, _ := localSettings[settings.settings[i].name].([]string)
+
+			globalOutput = strings.Join(globalSlice, "\n")
+			localOutput = strings.Join(localSlice, "\n")
+		}
+
+		if !settings.settings[i].hasGlobal {
+			globalOutput = ""
 		}
 
 		if !settings.settings[i].hasLocal {
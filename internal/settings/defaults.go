@@ -73,6 +73,13 @@ var defaults = []Setting{
 		hasLocal:     true,
 		hasGlobal:    true,
 	},
+	{
+		name:         "composerVersion",
+		defaultValue: composerVersion,
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
 	{
 		name:         "database",
 		defaultValue: "mariadb",
@@ -105,6 +112,28 @@ var defaults = []Setting{
 		hasGlobal:    true,
 		hasLocal:     true,
 	},
+	{
+		name:         "dns",
+		defaultValue: "false",
+		settingType:  "bool",
+		hasGlobal:    true,
+	},
+	{
+		name:         "dnsPort",
+		defaultValue: "5354",
+		settingType:  "int",
+		hasGlobal:    true,
+	},
+	{
+		name:         "domains",
+		defaultValue: "",
+		settingType:  "slice",
+		hasLocal:     true,
+		hasStartFlag: true,
+		startFlag: StartFlag{
+			Usage: "Extra domains, such as shop.test, the site answers to. Multiple domains should be separated by commas",
+		},
+	},
 	{
 		name:         "environment",
 		defaultValue: "local",
@@ -121,6 +150,13 @@ var defaults = []Setting{
 			Usage: "Sets the WP_ENVIRONMENT_TYPE for the site.",
 		},
 	},
+	{
+		name:         "healthCheckTimeout",
+		defaultValue: "120",
+		settingType:  "int",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
 	{
 		name:         "mailpit",
 		defaultValue: "false",
@@ -133,6 +169,24 @@ var defaults = []Setting{
 			Usage:     "Enable Mailpit when starting the container.",
 		},
 	},
+	{
+		name:         "manageHosts",
+		defaultValue: "false",
+		settingType:  "bool",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "mounts",
+		defaultValue: "",
+		settingType:  "slice",
+		hasLocal:     true,
+		hasGlobal:    true,
+		hasStartFlag: true,
+		startFlag: StartFlag{
+			Usage: "Mounts extra local plugins, themes or mu-plugins as <type>:<path>[:<slug>][:activate], separated by commas",
+		},
+	},
 	{
 		name:         "multisite",
 		defaultValue: "none",
@@ -149,6 +203,13 @@ var defaults = []Setting{
 			Usage:         "Creates your new site as a multisite installation.",
 		},
 	},
+	{
+		name:         "nodeVersion",
+		defaultValue: nodeVersion,
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
 	{
 		name:         "php",
 		defaultValue: "8.4",
@@ -156,6 +217,13 @@ var defaults = []Setting{
 		hasLocal:     true,
 		hasGlobal:    true,
 	},
+	{
+		name:         "phpExtensions",
+		defaultValue: "",
+		settingType:  "slice",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
 	{
 		name:         "plugins",
 		defaultValue: "",
@@ -164,7 +232,18 @@ var defaults = []Setting{
 		hasGlobal:    true,
 		hasStartFlag: true,
 		startFlag: StartFlag{
-			Usage: "Installs and activates the specified plugins. Multiple plugins should be separated by commas",
+			Usage: "Installs and activates the specified plugins, such as woocommerce@8.9.1. Multiple plugins should be separated by commas",
+		},
+	},
+	{
+		name:         "redis",
+		defaultValue: "false",
+		settingType:  "bool",
+		hasLocal:     true,
+		hasGlobal:    true,
+		hasStartFlag: true,
+		startFlag: StartFlag{
+			Usage: "Enable a Redis persistent object cache when starting the WordPress site.",
 		},
 	},
 	{
@@ -264,16 +343,48 @@ var defaults = []Setting{
 			Usage:     "Enable Xdebug when starting the WordPress site.",
 		},
 	},
+	{
+		name:         "xdebugClientHost",
+		defaultValue: xdebugClientHost,
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "xdebugIdeKey",
+		defaultValue: "",
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "xdebugMode",
+		defaultValue: xdebugMode,
+		settingType:  "string",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
+	{
+		name:         "xdebugPort",
+		defaultValue: "9003",
+		settingType:  "int",
+		hasLocal:     true,
+		hasGlobal:    true,
+	},
 }
 
 const (
-	certOS                 = "darwin"
+	composerVersion        = "2"
 	configFolderName       = ".config/kana"
 	defaultDirPermissions  = 0750
 	defaultFilePermissions = 0644
 	domain                 = "sites.kana.sh"
 	mariadbVersion         = "11"
+	maxPort                = 65535
 	mysqlVersion           = "9"
+	nodeVersion            = "lts"
+	xdebugClientHost       = "host.docker.internal"
+	xdebugMode             = "debug,develop"
 	rootCert               = "kana.root.pem"
 	rootKey                = "kana.root.key"
 	siteCert               = "kana.site.pem"
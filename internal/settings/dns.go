@@ -0,0 +1,133 @@
+package settings
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+)
+
+var dnsResolverFile = "/etc/systemd/resolved.conf.d/kana.conf"
+
+// GetDNSZones Returns the domains Kana's DNS server answers for, Kana's own domain followed by the extra domains of every site.
+func GetDNSZones(appDirectory string) ([]string, error) {
+	dnsZones := []string{domain}
+
+	siteCerts, err := getSiteCertNames(appDirectory)
+	if err != nil {
+		return dnsZones, err
+	}
+
+	for _, siteCertName := range siteCerts {
+		certDomains, err := getCertDomains(filepath.Join(appDirectory, "certs", "sites", siteCertName+".pem"))
+		if err != nil {
+			return dnsZones, err
+		}
+
+		for _, certDomain := range certDomains {
+			// Wildcards and subdomains are answered by the zone of their parent domain
+			if strings.HasPrefix(certDomain, "*.") || strings.HasSuffix(certDomain, "."+domain) {
+				continue
+			}
+
+			if !slices.Contains(dnsZones, certDomain) {
+				dnsZones = append(dnsZones, certDomain)
+			}
+		}
+	}
+
+	return dnsZones, nil
+}
+
+// IsDNSResolverInstalled Returns true if systemd-resolved has been told to send Kana's domains to its DNS server.
+func IsDNSResolverInstalled() bool {
+	_, err := os.Stat(dnsResolverFile)
+
+	return err == nil
+}
+
+// InstallDNSResolver Adds a systemd-resolved drop-in sending Kana's domains to its DNS server on the given port.
+// The drop-in is only rewritten, and systemd-resolved restarted, when the domains or port have changed.
+func InstallDNSResolver(appDirectory string, dnsPort int, consoleOutput *console.Console) error {
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("configuring the DNS resolver is only available on Linux with systemd-resolved")
+	}
+
+	dnsZones, err := GetDNSZones(appDirectory)
+	if err != nil {
+		return err
+	}
+
+	resolverContents := getDNSResolverContents(dnsPort, dnsZones)
+
+	existingContents, err := os.ReadFile(dnsResolverFile)
+	if err == nil && string(existingContents) == resolverContents {
+		return nil
+	}
+
+	consoleOutput.Println(fmt.Sprintf("Updating %s to use Kana's DNS server. You may be prompted for your password.", dnsResolverFile))
+
+	// The drop-in directory belongs to root so write it through sudo
+	err = runSudo(nil, "mkdir", "-p", filepath.Dir(dnsResolverFile))
+	if err != nil {
+		return err
+	}
+
+	err = runSudo(strings.NewReader(resolverContents), "tee", dnsResolverFile)
+	if err != nil {
+		return err
+	}
+
+	return runSudo(nil, "systemctl", "restart", "systemd-resolved")
+}
+
+// RemoveDNSResolver Removes the systemd-resolved drop-in so Kana's domains are looked up with the public DNS again.
+func RemoveDNSResolver(consoleOutput *console.Console) error {
+	if !IsDNSResolverInstalled() {
+		return nil
+	}
+
+	consoleOutput.Println(fmt.Sprintf("Removing %s. You may be prompted for your password.", dnsResolverFile))
+
+	err := runSudo(nil, "rm", "-f", dnsResolverFile)
+	if err != nil {
+		return err
+	}
+
+	return runSudo(nil, "systemctl", "restart", "systemd-resolved")
+}
+
+// getDNSResolverContents Returns a systemd-resolved drop-in routing the given domains to Kana's DNS server.
+// systemd-resolved can also send other queries to a global DNS server so the Corefile forwards anything else upstream.
+func getDNSResolverContents(dnsPort int, dnsZones []string) string {
+	routingDomains := []string{}
+
+	for _, dnsZone := range dnsZones {
+		routingDomains = append(routingDomains, "~"+dnsZone)
+	}
+
+	return fmt.Sprintf(
+		"# Generated by Kana. Any changes will be overwritten.\n[Resolve]\nDNS=127.0.0.1:%d\nDomains=%s\n",
+		dnsPort,
+		strings.Join(routingDomains, " "))
+}
+
+// runSudo Runs a command as root, returning its output as the error if it fails.
+func runSudo(stdin *strings.Reader, command ...string) error {
+	sudoCommand := execCommand("sudo", command...)
+
+	if stdin != nil {
+		sudoCommand.Stdin = stdin
+	}
+
+	output, err := sudoCommand.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("unable to run %s: %s", strings.Join(command, " "), strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDNSZones(t *testing.T) {
+	appDirectory := t.TempDir()
+
+	dnsZones, err := GetDNSZones(appDirectory)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sites.kana.sh"}, dnsZones)
+
+	_, err = EnsureSSLCerts(appDirectory, false, new(console.Console))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = EnsureSiteCert(appDirectory, "shop", []string{"shop.sites.kana.sh", "shop.test", "*.shop.sites.kana.sh"})
+	assert.NoError(t, err)
+
+	_, err = EnsureSiteCert(appDirectory, "blog", []string{"blog.sites.kana.sh", "blog.local", "shop.test"})
+	assert.NoError(t, err)
+
+	dnsZones, err = GetDNSZones(appDirectory)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sites.kana.sh", "blog.local", "shop.test"}, dnsZones)
+
+	corefile, err := os.ReadFile(filepath.Join(appDirectory, "config", "dns", "Corefile"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(corefile), "sites.kana.sh blog.local shop.test {")
+	assert.Contains(t, string(corefile), `answer "{{ .Name }} 60 IN A 127.0.0.1"`)
+	assert.Contains(t, string(corefile), ". {\n\tforward . /etc/resolv.conf")
+}
+
+func TestGetDNSResolverContents(t *testing.T) {
+	expected := "# Generated by Kana. Any changes will be overwritten.\n[Resolve]\nDNS=127.0.0.1:5354\nDomains=~sites.kana.sh ~shop.test\n"
+
+	assert.Equal(t, expected, getDNSResolverContents(5354, []string{"sites.kana.sh", "shop.test"}))
+}
@@ -1,6 +1,14 @@
 package settings
 
-import "fmt"
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
 
 func (s *Settings) GetURL() string {
 	return fmt.Sprintf("%s://%s", s.GetProtocol(), s.GetDomain())
@@ -10,6 +18,47 @@ func (s *Settings) GetDomain() string {
 	return fmt.Sprintf("%s.%s", s.Get("name"), domain)
 }
 
+// GetDomains Returns the site's domain followed by any extra domains from the domains setting.
+func (s *Settings) GetDomains() []string {
+	domains := []string{s.GetDomain()}
+
+	for _, extraDomain := range s.GetSlice("domains") {
+		extraDomain = strings.ToLower(strings.TrimSpace(extraDomain))
+
+		if extraDomain != "" && !slices.Contains(domains, extraDomain) {
+			domains = append(domains, extraDomain)
+		}
+	}
+
+	return domains
+}
+
+// GetCertDomains Returns the domains the site's own certificate covers. Along with its extra domains these are the
+// addresses of its helper containers and a wildcard for the sites of a subdomain multisite.
+func (s *Settings) GetCertDomains() []string {
+	domains := append(s.GetDomains(), "*."+s.GetDomain())
+
+	serviceNames := []string{}
+
+	for name, service := range s.GetServices() {
+		if service.HTTPPort != "" {
+			serviceNames = append(serviceNames, name)
+		}
+	}
+
+	sort.Strings(serviceNames)
+
+	for _, helperName := range append([]string{"mailpit", "phpmyadmin"}, serviceNames...) {
+		helperDomain := fmt.Sprintf("%s-%s", helperName, s.GetDomain())
+
+		if !slices.Contains(domains, helperDomain) {
+			domains = append(domains, helperDomain)
+		}
+	}
+
+	return domains
+}
+
 func (s *Settings) GetProtocol() string {
 	if s.GetBool("ssl") {
 		return "https"
@@ -17,3 +66,34 @@ func (s *Settings) GetProtocol() string {
 
 	return "http"
 }
+
+// validateDomains Checks every entry in the domains setting is a full hostname Kana doesn't already use.
+func validateDomains(value interface{}) error {
+	extraDomains := []string{}
+
+	if reflect.TypeOf(value).String() == "[]string" {
+		extraDomains = value.([]string)
+	} else if fmt.Sprint(value) != "" {
+		extraDomains = strings.Split(fmt.Sprint(value), ",")
+	}
+
+	validate := validator.New()
+
+	for _, extraDomain := range extraDomains {
+		extraDomain = strings.TrimSpace(extraDomain)
+
+		if extraDomain == "" {
+			continue
+		}
+
+		if validate.Var(extraDomain, "fqdn") != nil {
+			return fmt.Errorf("the domain, %s, must be a full hostname such as shop.test", extraDomain)
+		}
+
+		if strings.HasSuffix(strings.ToLower(extraDomain), "."+domain) {
+			return fmt.Errorf("the domain, %s, is already covered by Kana. Use a domain outside of %s", extraDomain, domain)
+		}
+	}
+
+	return nil
+}
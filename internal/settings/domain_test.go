@@ -1,6 +1,9 @@
 package settings
 
-import "testing"
+import (
+	"slices"
+	"testing"
+)
 
 func TestGetURL(t *testing.T) {
 	var tests = []struct {
@@ -105,3 +108,92 @@ func TestGetProtocol(t *testing.T) {
 		})
 	}
 }
+
+func TestGetDomains(t *testing.T) {
+	s := new(Settings)
+	s.settings = []Setting{
+		{name: "name", currentValue: "test"},
+		{name: "domains", currentValue: "shop.test, Mybrand.local,shop.test"},
+		{name: "multisite", currentValue: "none"},
+	}
+
+	expectedDomains := []string{"test.sites.kana.sh", "shop.test", "mybrand.local"}
+
+	if actualDomains := s.GetDomains(); !slices.Equal(actualDomains, expectedDomains) {
+		t.Errorf("Unexpected domains. Expected: %v, Got: %v", expectedDomains, actualDomains)
+	}
+}
+
+func TestGetCertDomains(t *testing.T) {
+	var tests = []struct {
+		name            string
+		domains         string
+		services        map[string]Service
+		expectedDomains []string
+	}{
+		{
+			"A site",
+			"",
+			nil,
+			[]string{
+				"test.sites.kana.sh",
+				"*.test.sites.kana.sh",
+				"mailpit-test.sites.kana.sh",
+				"phpmyadmin-test.sites.kana.sh"}},
+		{
+			"A site with extra domains and services",
+			"shop.test",
+			map[string]Service{
+				"search":   {Image: "elasticsearch:8.15.0", HTTPPort: "9200"},
+				"frontend": {Image: "node:lts", HTTPPort: "3000"},
+				"cache":    {Image: "memcached:1.6"},
+			},
+			[]string{
+				"test.sites.kana.sh",
+				"shop.test",
+				"*.test.sites.kana.sh",
+				"mailpit-test.sites.kana.sh",
+				"phpmyadmin-test.sites.kana.sh",
+				"frontend-test.sites.kana.sh",
+				"search-test.sites.kana.sh"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := new(Settings)
+			s.settings = []Setting{
+				{name: "name", currentValue: "test"},
+				{name: "domains", currentValue: test.domains},
+			}
+			s.services = test.services
+
+			if actualDomains := s.GetCertDomains(); !slices.Equal(actualDomains, test.expectedDomains) {
+				t.Errorf("Unexpected domains. Expected: %v, Got: %v", test.expectedDomains, actualDomains)
+			}
+		})
+	}
+}
+
+func TestValidateDomains(t *testing.T) {
+	var tests = []struct {
+		name    string
+		value   interface{}
+		wantErr bool
+	}{
+		{"No domains", "", false},
+		{"Valid domains", []string{"shop.test", "mybrand.local", ""}, false},
+		{"Comma separated domains", "shop.test,www.example.com", false},
+		{"Not a full hostname", []string{"localhost"}, true},
+		{"Invalid characters", []string{"shop_test.local"}, true},
+		{"A Kana domain", []string{"other.sites.kana.sh"}, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateDomains(test.value)
+			if (err != nil) != test.wantErr {
+				t.Errorf("validateDomains() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package settings
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// The places a plugin or theme can be installed from.
+const (
+	ExtensionSourceWordPress = "wordpress.org"
+	ExtensionSourceZip       = "zip"
+	ExtensionSourcePath      = "path"
+	ExtensionSourceGit       = "git"
+)
+
+// Extension is a plugin or theme entry from the plugins or theme settings.
+type Extension struct {
+	Entry   string
+	Source  string
+	Slug    string
+	Version string
+	URL     string
+	Ref     string
+	Path    string
+}
+
+var (
+	extensionSlugPattern    = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+	extensionVersionPattern = regexp.MustCompile(`^[A-Za-z0-9.+-]+$`)
+	extensionRefPattern     = regexp.MustCompile(`^[A-Za-z0-9._/-]+$`)
+	// Zip files are usually named after the slug, optionally followed by the version.
+	zipVersionPattern = regexp.MustCompile(`[.-]v?[0-9][A-Za-z0-9.+-]*$`)
+)
+
+// ParseExtension Reads a plugin or theme entry. Entries can be a wordpress.org slug with an optional version, such as
+// woocommerce@8.9.1, an https URL to a zip file, a local path or a git repository prefixed with git+ and followed by an
+// optional #ref.
+func ParseExtension(entry string) (Extension, error) {
+	entry = strings.TrimSpace(entry)
+
+	extension := Extension{Entry: entry}
+
+	switch {
+	case entry == "":
+		return extension, fmt.Errorf("plugin and theme entries can't be empty")
+	case strings.HasPrefix(entry, "git+"):
+		return parseGitExtension(extension)
+	case strings.HasPrefix(entry, "http://") || strings.HasPrefix(entry, "https://"):
+		return parseZipExtension(extension)
+	case filepath.IsAbs(entry) || entry == "~" || strings.HasPrefix(entry, "~/") ||
+		strings.HasPrefix(entry, "./") || strings.HasPrefix(entry, "../"):
+		extension.Source = ExtensionSourcePath
+		extension.Path = entry
+		extension.Slug = filepath.Base(filepath.Clean(entry))
+
+		return extension, nil
+	}
+
+	extension.Source = ExtensionSourceWordPress
+	extension.Slug, extension.Version, _ = strings.Cut(entry, "@")
+
+	if !extensionSlugPattern.MatchString(extension.Slug) {
+		return extension, fmt.Errorf(
+			"the plugin or theme, %s, is invalid. Use a wordpress.org slug, a zip URL, a local path or a git+ repository",
+			entry)
+	}
+
+	if strings.Contains(entry, "@") && !extensionVersionPattern.MatchString(extension.Version) {
+		return extension, fmt.Errorf(
+			"the version of %s is invalid. Versions are written as slug@version, for example woocommerce@8.9.1",
+			entry)
+	}
+
+	return extension, nil
+}
+
+// String Returns the entry as it would be written in the plugins or theme settings.
+func (e Extension) String() string {
+	if e.Source == ExtensionSourceWordPress && e.Version != "" {
+		return fmt.Sprintf("%s@%s", e.Slug, e.Version)
+	}
+
+	if e.Source == ExtensionSourceWordPress {
+		return e.Slug
+	}
+
+	return e.Entry
+}
+
+// parseGitExtension Reads a git+<repository>#<ref> entry.
+func parseGitExtension(extension Extension) (Extension, error) {
+	extension.Source = ExtensionSourceGit
+	extension.URL, extension.Ref, _ = strings.Cut(strings.TrimPrefix(extension.Entry, "git+"), "#")
+
+	repositoryURL, err := url.Parse(extension.URL)
+	if err != nil || repositoryURL.Host == "" {
+		return extension, fmt.Errorf("the git repository in %s is invalid", extension.Entry)
+	}
+
+	extension.Slug = strings.TrimSuffix(path.Base(repositoryURL.Path), ".git")
+
+	if !extensionSlugPattern.MatchString(extension.Slug) {
+		return extension, fmt.Errorf("unable to find a plugin or theme name in the git repository %s", extension.URL)
+	}
+
+	if extension.Ref != "" && !extensionRefPattern.MatchString(extension.Ref) {
+		return extension, fmt.Errorf("the git ref in %s is invalid", extension.Entry)
+	}
+
+	return extension, nil
+}
+
+// parseZipExtension Reads an entry that points at a zip file. The version, if the file name has one, is used to
+// replace an installed copy when the URL changes.
+func parseZipExtension(extension Extension) (Extension, error) {
+	extension.Source = ExtensionSourceZip
+	extension.URL = extension.Entry
+
+	zipURL, err := url.Parse(extension.Entry)
+	if err != nil || zipURL.Host == "" || !strings.HasSuffix(strings.ToLower(zipURL.Path), ".zip") {
+		return extension, fmt.Errorf("the plugin or theme URL, %s, must point to a zip file", extension.Entry)
+	}
+
+	if zipURL.Scheme != "https" {
+		return extension, fmt.Errorf("the plugin or theme URL, %s, must use https", extension.Entry)
+	}
+
+	fileName := strings.TrimSuffix(path.Base(zipURL.Path), path.Ext(zipURL.Path))
+	version := zipVersionPattern.FindString(fileName)
+
+	extension.Slug = strings.TrimSuffix(fileName, version)
+	extension.Version = strings.TrimPrefix(strings.TrimLeft(version, ".-"), "v")
+
+	return extension, nil
+}
+
+// validateExtensions Checks every entry in the plugins or theme settings.
+func validateExtensions(value interface{}) error {
+	entries := []string{}
+
+	if reflect.TypeOf(value).String() == "[]string" {
+		entries = value.([]string)
+	} else if fmt.Sprint(value) != "" {
+		entries = strings.Split(fmt.Sprint(value), ",")
+	}
+
+	for _, entry := range entries {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		_, err := ParseExtension(entry)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExtension(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    string
+		expected Extension
+		wantErr  bool
+	}{
+		{
+			"slug",
+			"woocommerce",
+			Extension{Entry: "woocommerce", Source: ExtensionSourceWordPress, Slug: "woocommerce"},
+			false},
+		{
+			"slug with version",
+			"woocommerce@8.9.1",
+			Extension{Entry: "woocommerce@8.9.1", Source: ExtensionSourceWordPress, Slug: "woocommerce", Version: "8.9.1"},
+			false},
+		{
+			"zip",
+			"https://downloads.wordpress.org/plugin/query-monitor.3.16.0.zip",
+			Extension{
+				Entry:   "https://downloads.wordpress.org/plugin/query-monitor.3.16.0.zip",
+				Source:  ExtensionSourceZip,
+				Slug:    "query-monitor",
+				Version: "3.16.0",
+				URL:     "https://downloads.wordpress.org/plugin/query-monitor.3.16.0.zip"},
+			false},
+		{
+			"zip with a v prefixed version",
+			"https://example.com/releases/my-plugin-v1.1.zip",
+			Extension{
+				Entry:   "https://example.com/releases/my-plugin-v1.1.zip",
+				Source:  ExtensionSourceZip,
+				Slug:    "my-plugin",
+				Version: "1.1",
+				URL:     "https://example.com/releases/my-plugin-v1.1.zip"},
+			false},
+		{
+			"zip without a version",
+			"https://example.com/my-plugin.zip",
+			Extension{
+				Entry:  "https://example.com/my-plugin.zip",
+				Source: ExtensionSourceZip,
+				Slug:   "my-plugin",
+				URL:    "https://example.com/my-plugin.zip"},
+			false},
+		{
+			"relative path",
+			"./plugins/my-plugin/",
+			Extension{Entry: "./plugins/my-plugin/", Source: ExtensionSourcePath, Slug: "my-plugin", Path: "./plugins/my-plugin/"},
+			false},
+		{
+			"git with ref",
+			"git+https://github.com/owner/my-theme.git#v1.2.0",
+			Extension{
+				Entry:  "git+https://github.com/owner/my-theme.git#v1.2.0",
+				Source: ExtensionSourceGit,
+				Slug:   "my-theme",
+				URL:    "https://github.com/owner/my-theme.git",
+				Ref:    "v1.2.0"},
+			false},
+		{
+			"git without ref",
+			"git+https://gitlab.com/owner/my-plugin",
+			Extension{
+				Entry:  "git+https://gitlab.com/owner/my-plugin",
+				Source: ExtensionSourceGit,
+				Slug:   "my-plugin",
+				URL:    "https://gitlab.com/owner/my-plugin"},
+			false},
+		{"git without a host", "git+my-plugin.git", Extension{}, true},
+		{"empty", "", Extension{}, true},
+		{"empty version", "woocommerce@", Extension{}, true},
+		{"invalid slug", "my plugin", Extension{}, true},
+		{"url without zip", "https://example.com/plugin", Extension{}, true},
+		{"zip over http", "http://example.com/my-plugin.1.0.zip", Extension{}, true},
+		{"invalid git ref", "git+https://github.com/owner/repo.git#bad ref", Extension{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			extension, err := ParseExtension(tt.entry)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseExtension() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr {
+				assert.Equal(t, tt.expected, extension)
+			}
+		})
+	}
+}
+
+func TestExtensionString(t *testing.T) {
+	extension, err := ParseExtension("woocommerce")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	extension.Version = "8.9.1"
+
+	assert.Equal(t, "woocommerce@8.9.1", extension.String())
+
+	extension, err = ParseExtension("git+https://github.com/owner/repo.git#main")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "git+https://github.com/owner/repo.git#main", extension.String())
+}
+
+func TestValidateExtensions(t *testing.T) {
+	assert.NoError(t, validateExtensions(""))
+	assert.NoError(t, validateExtensions([]string{"woocommerce@8.9.1", "", "./my-plugin"}))
+	assert.NoError(t, validateExtensions("woocommerce,query-monitor@3.16.0"))
+	assert.Error(t, validateExtensions([]string{"woocommerce", "bad slug"}))
+}
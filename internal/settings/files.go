@@ -1,7 +1,9 @@
 package settings
 
 import (
+	"bytes"
 	_ "embed"
+	"fmt"
 	"os"
 	"path/filepath"
 	"text/template"
@@ -13,6 +15,9 @@ var SubDomainMultisiteHtaccess string
 //go:embed templates/subdirectory.htaccess
 var SubDirectoryMultisiteHtaccess string
 
+//go:embed templates/Corefile
+var Corefile string
+
 //go:embed templates/dynamic.toml
 var DynamicToml string
 
@@ -22,7 +27,19 @@ var TraefikToml string
 //go:embed templates/kana-local-development.php
 var KanaWordPressPlugin string
 
+//go:embed templates/kana-mu-plugin-loader.php
+var MUPluginLoader string
+
+//go:embed templates/wordpress.Dockerfile
+var WordPressDockerfile string
+
 var configFiles = []File{
+	{
+		Name:        "Corefile",
+		Template:    Corefile,
+		LocalPath:   "config/dns",
+		Permissions: os.FileMode(defaultFilePermissions),
+	},
 	{
 		Name:        "dynamic.toml",
 		Template:    DynamicToml,
@@ -64,6 +81,31 @@ func EnsureKanaPlugin(siteDirectory, version, siteName string) error {
 	return tmpl.Execute(myFile, pluginVars)
 }
 
+// EnsureMUPluginLoader Writes the file that loads a must-use plugin folder mounted by Kana.
+func EnsureMUPluginLoader(siteDirectory, slug string) error {
+	tmpl := template.Must(template.New("muPluginLoader").Parse(MUPluginLoader))
+
+	pluginPath := filepath.Join(siteDirectory, "wp-content", "mu-plugins")
+
+	err := os.MkdirAll(pluginPath, os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return err
+	}
+
+	myFile, err := os.Create(filepath.Join(pluginPath, GetMUPluginLoaderName(slug)))
+	if err != nil {
+		return err
+	}
+	defer myFile.Close()
+
+	return tmpl.Execute(myFile, map[string]string{"Slug": slug})
+}
+
+// GetMUPluginLoaderName Returns the file name of the loader for a must-use plugin folder mounted by Kana.
+func GetMUPluginLoaderName(slug string) string {
+	return fmt.Sprintf("kana-mount-%s.php", slug)
+}
+
 // GetDefaultFilePermissions returns the default directory permissions and the default file permissions.
 func GetDefaultFilePermissions() (dirPerms, filePerms int) {
 	return defaultDirPermissions, defaultFilePermissions
@@ -80,6 +122,22 @@ func GetHtaccess(multisite string) string {
 
 // ensureStaticConfigFiles Ensures the application's static config files have been generated and are where they need to be.
 func ensureStaticConfigFiles(appDirectory string) error {
+	// Traefik's dynamic config lists the certificates of the sites that need their own
+	siteCerts, err := getSiteCertNames(appDirectory)
+	if err != nil {
+		return err
+	}
+
+	dnsZones, err := GetDNSZones(appDirectory)
+	if err != nil {
+		return err
+	}
+
+	configFileData := ConfigFileData{
+		SiteCerts: siteCerts,
+		DNSZones:  dnsZones,
+	}
+
 	for _, file := range configFiles {
 		filePath := filepath.Join(appDirectory, file.LocalPath)
 		destFile := filepath.Join(appDirectory, file.LocalPath, file.Name)
@@ -88,9 +146,14 @@ func ensureStaticConfigFiles(appDirectory string) error {
 			return err
 		}
 
-		finalTemplate := []byte(file.Template)
+		var finalTemplate bytes.Buffer
+
+		err = template.Must(template.New(file.Name).Parse(file.Template)).Execute(&finalTemplate, configFileData)
+		if err != nil {
+			return err
+		}
 
-		err := os.WriteFile(destFile, finalTemplate, file.Permissions)
+		err = os.WriteFile(destFile, finalTemplate.Bytes(), file.Permissions)
 		if err != nil {
 			return err
 		}
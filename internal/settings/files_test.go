@@ -1,9 +1,16 @@
 package settings
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestEnsureKanaPlugin(t *testing.T) {
@@ -27,6 +34,23 @@ func TestEnsureKanaPlugin(t *testing.T) {
 		t.Errorf("EnsureKanaPlugin returned an error: %v", err)
 	}
 }
+func TestEnsureMUPluginLoader(t *testing.T) {
+	siteDirectory := t.TempDir()
+
+	err := EnsureMUPluginLoader(siteDirectory, "company")
+	if err != nil {
+		t.Errorf("EnsureMUPluginLoader returned an error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(siteDirectory, "wp-content", "mu-plugins", "kana-mount-company.php"))
+	if err != nil {
+		t.Errorf("Failed to create the mu-plugin loader: %v", err)
+	}
+
+	if !strings.Contains(string(content), "__DIR__ . '/company/*.php'") {
+		t.Errorf("The mu-plugin loader doesn't load the company folder")
+	}
+}
 func TestGetDefaultFilePermissions(t *testing.T) {
 	dirPerms, filePerms := GetDefaultFilePermissions()
 
@@ -69,3 +93,54 @@ func TestEnsureStaticConfigFiles(t *testing.T) {
 		t.Errorf("EnsureKanaPlugin returned an error: %v", err)
 	}
 }
+
+func TestEnsureSiteCert(t *testing.T) {
+	appDirectory := t.TempDir()
+
+	changed, err := EnsureSSLCerts(appDirectory, false, new(console.Console))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, changed)
+
+	// The shared certificate is kept until it is close to expiring
+	changed, err = EnsureSSLCerts(appDirectory, false, new(console.Console))
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	domains := []string{"test.sites.kana.sh", "shop.test"}
+
+	changed, err = EnsureSiteCert(appDirectory, "test", domains)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	certContents, err := os.ReadFile(filepath.Join(appDirectory, "certs", "sites", "test.pem"))
+	assert.NoError(t, err)
+
+	block, _ := pem.Decode(certContents)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	assert.NoError(t, err)
+	assert.Equal(t, domains, cert.DNSNames)
+	assert.Len(t, cert.IPAddresses, 2)
+
+	dynamicConfig, err := os.ReadFile(filepath.Join(appDirectory, "config", "traefik", "dynamic.toml"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(dynamicConfig), `certFile = "/var/certs/sites/test.pem"`)
+
+	// The certificate is only replaced when the domains change
+	changed, err = EnsureSiteCert(appDirectory, "test", domains)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	changed, err = EnsureSiteCert(appDirectory, "test", []string{})
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	_, err = os.Stat(filepath.Join(appDirectory, "certs", "sites", "test.pem"))
+	assert.True(t, os.IsNotExist(err))
+
+	dynamicConfig, err = os.ReadFile(filepath.Join(appDirectory, "config", "traefik", "dynamic.toml"))
+	assert.NoError(t, err)
+	assert.NotContains(t, string(dynamicConfig), "/var/certs/sites/")
+}
@@ -48,12 +48,15 @@ func processStartFlags(cmd *cobra.Command, settings *Settings) error {
 				}
 
 				if settings.settings[i].settingType == "slice" {
-					strings.Split(cmd.Flags().Lookup("plugins").Value.String(), ",")
+					sliceValue, _ := cmd.Flags().GetStringSlice(settings.settings[i].name)
+
+					err = settings.Set(settings.settings[i].name, sliceValue)
 				} else {
 					err = settings.Set(settings.settings[i].name, cmd.Flags().Lookup(settings.settings[i].name).Value.String())
-					if err != nil {
-						return err
-					}
+				}
+
+				if err != nil {
+					return err
 				}
 			}
 		}
@@ -0,0 +1,73 @@
+package settings
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+)
+
+var hostsFile = "/etc/hosts"
+
+// UpdateHostsFile Points the given domains at the local machine in /etc/hosts, replacing any entries Kana added for
+// the site before. Passing no domains removes the site's entries.
+func UpdateHostsFile(name string, domains []string, consoleOutput *console.Console) error {
+	contents, err := os.ReadFile(hostsFile)
+	if err != nil {
+		return err
+	}
+
+	newContents := getHostsContents(string(contents), name, domains)
+
+	if newContents == string(contents) {
+		return nil
+	}
+
+	consoleOutput.Println(fmt.Sprintf("Updating %s for the site's domains. You may be prompted for your password.", hostsFile))
+
+	// The hosts file belongs to root so write it through sudo
+	writeHostsCommand := execCommand("sudo", "tee", hostsFile)
+	writeHostsCommand.Stdin = strings.NewReader(newContents)
+
+	output, err := writeHostsCommand.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("unable to update %s: %s", hostsFile, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// getHostsContents Returns the hosts file with the site's block of entries replaced by one for the given domains.
+func getHostsContents(contents, name string, domains []string) string {
+	startMarker := fmt.Sprintf("# Kana %s start", name)
+	endMarker := fmt.Sprintf("# Kana %s end", name)
+
+	lines := []string{}
+	inBlock := false
+
+	for _, line := range strings.Split(strings.TrimRight(contents, "\n"), "\n") {
+		switch strings.TrimSpace(line) {
+		case startMarker:
+			inBlock = true
+			continue
+		case endMarker:
+			inBlock = false
+			continue
+		}
+
+		if !inBlock {
+			lines = append(lines, line)
+		}
+	}
+
+	if len(domains) > 0 {
+		lines = append(lines,
+			startMarker,
+			fmt.Sprintf("127.0.0.1 %s", strings.Join(domains, " ")),
+			fmt.Sprintf("::1 %s", strings.Join(domains, " ")),
+			endMarker)
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
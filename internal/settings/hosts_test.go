@@ -0,0 +1,43 @@
+package settings
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetHostsContents(t *testing.T) {
+	var tests = []struct {
+		name     string
+		contents string
+		domains  []string
+		expected string
+	}{
+		{
+			"Add a site",
+			"127.0.0.1 localhost\n",
+			[]string{"shop.test"},
+			"127.0.0.1 localhost\n# Kana shop start\n127.0.0.1 shop.test\n::1 shop.test\n# Kana shop end\n"},
+		{
+			"Replace a site",
+			"127.0.0.1 localhost\n# Kana shop start\n127.0.0.1 old.test\n::1 old.test\n# Kana shop end\n# Kana other start\n" +
+				"127.0.0.1 other.test\n# Kana other end\n",
+			[]string{"shop.test", "www.shop.test"},
+			"127.0.0.1 localhost\n# Kana other start\n127.0.0.1 other.test\n# Kana other end\n# Kana shop start\n" +
+				"127.0.0.1 shop.test www.shop.test\n::1 shop.test www.shop.test\n# Kana shop end\n"},
+		{
+			"Remove a site",
+			"127.0.0.1 localhost\n# Kana shop start\n127.0.0.1 shop.test\n::1 shop.test\n# Kana shop end\n",
+			[]string{},
+			"127.0.0.1 localhost\n"},
+		{
+			"Leave a file without the site alone",
+			"127.0.0.1 localhost\n",
+			[]string{},
+			"127.0.0.1 localhost\n"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, getHostsContents(test.contents, "shop", test.domains), test.name)
+	}
+}
@@ -72,6 +72,16 @@ func loadKoanfOptions(settingsType string, settings *Settings) error {
 	}
 
 	if settingsType == "local" {
+		err = settings.loadServices(ko)
+		if err != nil {
+			return err
+		}
+
+		err = settings.loadPHPIni(ko)
+		if err != nil {
+			return err
+		}
+
 		settings.local = ko
 	} else {
 		settings.global = ko
@@ -147,6 +147,24 @@ func (_m *Koanf) Strings(path string) []string {
 	return r0
 }
 
+// Unmarshal provides a mock function with given fields: path, o
+func (_m *Koanf) Unmarshal(path string, o interface{}) error {
+	ret := _m.Called(path, o)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unmarshal")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, interface{}) error); ok {
+		r0 = rf(path, o)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewKoanf creates a new instance of Koanf. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewKoanf(t interface {
@@ -0,0 +1,161 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/helpers"
+)
+
+// The folders of wp-content a local directory can be mounted into.
+var mountTypes = []string{
+	"plugin",
+	"theme",
+	"mu-plugin",
+}
+
+// Mount is an extra local directory from the mounts setting, written as <type>:<path>[:<slug>][:activate].
+type Mount struct {
+	Entry    string
+	Type     string
+	Path     string
+	Slug     string
+	Activate bool
+}
+
+// ParseMount Reads an entry of the mounts setting such as plugin:../my-other-plugin or theme:~/themes/base:my-theme:activate.
+func ParseMount(entry string) (Mount, error) {
+	entry = strings.TrimSpace(entry)
+
+	mount := Mount{Entry: entry}
+
+	mountParts := strings.Split(entry, ":")
+	if len(mountParts) < 2 || len(mountParts) > 4 || mountParts[1] == "" {
+		return mount, fmt.Errorf("the mount, %s, is invalid. Mounts are written as <type>:<path>[:<slug>][:activate]", entry)
+	}
+
+	mount.Type = mountParts[0]
+	mount.Path = mountParts[1]
+
+	if !helpers.IsValidString(mount.Type, mountTypes) {
+		return mount, fmt.Errorf("the type of the mount, %s, must be one of %s", entry, strings.Join(mountTypes, ", "))
+	}
+
+	for _, option := range mountParts[2:] {
+		switch {
+		case option == "activate" && !mount.Activate:
+			mount.Activate = true
+		case mount.Slug == "" && !mount.Activate && extensionSlugPattern.MatchString(option):
+			mount.Slug = option
+		default:
+			return mount, fmt.Errorf("the mount, %s, is invalid. Mounts are written as <type>:<path>[:<slug>][:activate]", entry)
+		}
+	}
+
+	if mount.Slug == "" {
+		mount.Slug = filepath.Base(filepath.Clean(mount.Path))
+	}
+
+	if !extensionSlugPattern.MatchString(mount.Slug) {
+		return mount, fmt.Errorf("unable to find a slug for the mount, %s. Add one as <type>:<path>:<slug>", entry)
+	}
+
+	// Must-use plugins load automatically so there is nothing to activate
+	if mount.Activate && mount.Type == "mu-plugin" {
+		return mount, fmt.Errorf("the mount, %s, can't be activated as must-use plugins are always active", entry)
+	}
+
+	return mount, nil
+}
+
+// validateMounts Checks every entry in the mounts setting.
+func validateMounts(value interface{}) error {
+	entries := []string{}
+
+	if reflect.TypeOf(value).String() == "[]string" {
+		entries = value.([]string)
+	} else if fmt.Sprint(value) != "" {
+		entries = strings.Split(fmt.Sprint(value), ",")
+	}
+
+	for _, entry := range entries {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		_, err := ParseMount(entry)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteLinkMounts Records the directories mounted into a site so Kana can find the site from any of them.
+func WriteLinkMounts(siteDirectory string, directories []string) error {
+	linkConfigFile := filepath.Join(siteDirectory, "link.json")
+
+	content, err := os.ReadFile(linkConfigFile)
+	if err != nil {
+		return err
+	}
+
+	siteLink := map[string]interface{}{}
+
+	err = json.Unmarshal(content, &siteLink)
+	if err != nil {
+		return err
+	}
+
+	if len(directories) == 0 {
+		delete(siteLink, "mounts")
+	} else {
+		siteLink["mounts"] = directories
+	}
+
+	jsonBytes, err := json.MarshalIndent(siteLink, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	_, filePerms := GetDefaultFilePermissions()
+
+	return os.WriteFile(linkConfigFile, jsonBytes, os.FileMode(filePerms))
+}
+
+// findMountingSite Looks for a site that mounts the given directory, returning its name and the directory it is linked to.
+func findMountingSite(workingDirectory, appDirectory string) (name, link string, found bool) {
+	sitesDirectory := filepath.Join(appDirectory, "sites")
+
+	sites, err := os.ReadDir(sitesDirectory)
+	if err != nil {
+		return "", "", false
+	}
+
+	for _, site := range sites {
+		content, err := os.ReadFile(filepath.Join(sitesDirectory, site.Name(), "link.json"))
+		if err != nil {
+			continue
+		}
+
+		var siteLink struct {
+			Link   string   `json:"link"`
+			Mounts []string `json:"mounts"`
+		}
+
+		if json.Unmarshal(content, &siteLink) != nil {
+			continue
+		}
+
+		if helpers.IsValidString(workingDirectory, siteLink.Mounts) {
+			return site.Name(), siteLink.Link, true
+		}
+	}
+
+	return "", "", false
+}
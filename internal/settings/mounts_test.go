@@ -0,0 +1,98 @@
+package settings
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMount(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    string
+		expected Mount
+		wantErr  bool
+	}{
+		{
+			"plugin",
+			"plugin:../my-plugin",
+			Mount{Entry: "plugin:../my-plugin", Type: "plugin", Path: "../my-plugin", Slug: "my-plugin"},
+			false},
+		{
+			"theme with slug and activation",
+			"theme:~/themes/base:my-theme:activate",
+			Mount{Entry: "theme:~/themes/base:my-theme:activate", Type: "theme", Path: "~/themes/base", Slug: "my-theme", Activate: true},
+			false},
+		{
+			"activation without slug",
+			"plugin:/projects/my-plugin/:activate",
+			Mount{Entry: "plugin:/projects/my-plugin/:activate", Type: "plugin", Path: "/projects/my-plugin/", Slug: "my-plugin", Activate: true},
+			false},
+		{
+			"mu-plugin",
+			"mu-plugin:./mu-plugins:company",
+			Mount{Entry: "mu-plugin:./mu-plugins:company", Type: "mu-plugin", Path: "./mu-plugins", Slug: "company"},
+			false},
+		{"missing path", "plugin", Mount{}, true},
+		{"empty path", "plugin:", Mount{}, true},
+		{"invalid type", "widget:../my-widget", Mount{}, true},
+		{"invalid slug", "plugin:../my-plugin:my slug", Mount{}, true},
+		{"too many options", "plugin:../my-plugin:slug:activate:extra", Mount{}, true},
+		{"activated mu-plugin", "mu-plugin:../mu:activate", Mount{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mount, err := ParseMount(tt.entry)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr {
+				assert.Equal(t, tt.expected, mount)
+			}
+		})
+	}
+}
+
+func TestValidateMounts(t *testing.T) {
+	assert.NoError(t, validateMounts(""))
+	assert.NoError(t, validateMounts([]string{"plugin:../a", "", "theme:../b:b:activate"}))
+	assert.NoError(t, validateMounts("plugin:../a,mu-plugin:../c"))
+	assert.Error(t, validateMounts([]string{"plugin:../a", "../b"}))
+}
+
+func TestFindMountingSite(t *testing.T) {
+	appDirectory := t.TempDir()
+	siteDirectory := filepath.Join(appDirectory, "sites", "suite")
+
+	err := WriteLinkConfig(siteDirectory, "/projects/suite", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = WriteLinkMounts(siteDirectory, []string{"/projects/addon"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name, link, found := findMountingSite("/projects/addon", appDirectory)
+
+	assert.True(t, found)
+	assert.Equal(t, "suite", name)
+	assert.Equal(t, "/projects/suite", link)
+
+	_, _, found = findMountingSite("/projects/other", appDirectory)
+
+	assert.False(t, found)
+
+	err = WriteLinkMounts(siteDirectory, []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, found = findMountingSite("/projects/addon", appDirectory)
+
+	assert.False(t, found)
+}
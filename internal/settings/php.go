@@ -0,0 +1,113 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var (
+	phpExtensionPattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+	phpIniKeyPattern    = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+)
+
+// GetPHPIni Returns the php.ini directives declared in the site's local config.
+func (s *Settings) GetPHPIni() map[string]string {
+	if s.phpIni == nil {
+		return map[string]string{}
+	}
+
+	return s.phpIni
+}
+
+// loadPHPIni Reads the phpIni section of a local config file.
+func (s *Settings) loadPHPIni(ko Koanf) error {
+	if !ko.Exists("phpIni") {
+		return nil
+	}
+
+	phpIni := map[string]interface{}{}
+
+	err := ko.Unmarshal("phpIni", &phpIni)
+	if err != nil {
+		return fmt.Errorf("the phpIni settings in your configuration are invalid: %s", err)
+	}
+
+	return s.setPHPIni(phpIni)
+}
+
+// setPHPIniFromJSON Sets the php.ini directives from decoded JSON, such as the settings restored from an archive.
+func (s *Settings) setPHPIniFromJSON(value interface{}) error {
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	phpIni := map[string]interface{}{}
+
+	err = json.Unmarshal(jsonBytes, &phpIni)
+	if err != nil {
+		return fmt.Errorf("the phpIni settings in your configuration are invalid: %s", err)
+	}
+
+	return s.setPHPIni(phpIni)
+}
+
+// setPHPIni Validates and sets the php.ini directives. Numbers and booleans are accepted as JSON makes them easier to write.
+func (s *Settings) setPHPIni(values map[string]interface{}) error {
+	phpIni := map[string]string{}
+
+	for key, value := range values {
+		if !phpIniKeyPattern.MatchString(key) {
+			return fmt.Errorf("the php.ini directive, %s, is invalid", key)
+		}
+
+		switch value.(type) {
+		case map[string]interface{}, []interface{}, nil:
+			return fmt.Errorf("the value for the php.ini directive %s must be a string, number or boolean", key)
+		}
+
+		stringValue := fmt.Sprint(value)
+
+		if strings.ContainsAny(stringValue, "\r\n") {
+			return fmt.Errorf("the value for the php.ini directive %s must be on a single line", key)
+		}
+
+		phpIni[key] = stringValue
+	}
+
+	s.phpIni = phpIni
+
+	return nil
+}
+
+// validatePHPExtensions Checks the phpExtensions setting, which can be set from a slice or a comma separated string.
+func validatePHPExtensions(value interface{}) error {
+	extensions := []string{}
+
+	if reflect.TypeOf(value).String() == "[]string" {
+		extensions = value.([]string)
+	} else if fmt.Sprint(value) != "" {
+		extensions = strings.Split(fmt.Sprint(value), ",")
+	}
+
+	for _, extension := range extensions {
+		if extension == "" {
+			continue
+		}
+
+		if extension == "xdebug" {
+			return fmt.Errorf("xdebug can't be added to phpExtensions. Use the xdebug setting or the `kana xdebug` command instead")
+		}
+
+		if !phpExtensionPattern.MatchString(extension) {
+			return fmt.Errorf(
+				"the PHP extension, %s, is invalid. Extensions are named as PHP reports them, for example intl or imagick",
+				extension)
+		}
+	}
+
+	return nil
+}
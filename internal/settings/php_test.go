@@ -0,0 +1,70 @@
+package settings
+
+import (
+	"testing"
+)
+
+func TestValidatePHPExtensions(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   interface{}
+		wantErr bool
+	}{
+		{"empty", "", false},
+		{"slice", []string{"intl", "imagick"}, false},
+		{"string", "intl,pdo_pgsql", false},
+		{"empty item", []string{""}, false},
+		{"xdebug", []string{"intl", "xdebug"}, true},
+		{"uppercase", "Intl", true},
+		{"version", "redis-6.0.2", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePHPExtensions(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validatePHPExtensions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetPHPIniFromJSON(t *testing.T) {
+	s := new(Settings)
+
+	if len(s.GetPHPIni()) != 0 {
+		t.Errorf("Expected no php.ini directives, got %d", len(s.GetPHPIni()))
+	}
+
+	err := s.setPHPIniFromJSON(map[string]interface{}{
+		"memory_limit":       "512M",
+		"max_execution_time": float64(300),
+		"opcache.enable":     false,
+	})
+	if err != nil {
+		t.Fatalf("setPHPIniFromJSON returned an error: %v", err)
+	}
+
+	phpIni := s.GetPHPIni()
+
+	if phpIni["memory_limit"] != "512M" || phpIni["max_execution_time"] != "300" || phpIni["opcache.enable"] != "false" {
+		t.Errorf("Unexpected php.ini directives: %+v", phpIni)
+	}
+
+	allSettings := s.GetAll("local")
+	if _, ok := allSettings["phpIni"]; !ok {
+		t.Error("Expected the php.ini directives to be included in the local settings")
+	}
+
+	invalid := []map[string]interface{}{
+		{"memory limit": "512M"},
+		{"memory_limit": "512M\nextension=evil.so"},
+		{"memory_limit": []interface{}{"512M"}},
+	}
+
+	for _, phpIni := range invalid {
+		if s.setPHPIniFromJSON(phpIni) == nil {
+			t.Errorf("Expected an error for %+v", phpIni)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var serviceNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// reservedServiceNames are used by the containers Kana starts itself.
+var reservedServiceNames = []string{
+	"database",
+	"mailpit",
+	"phpmyadmin",
+	"redis",
+	"wordpress",
+	"wordpress_cli",
+}
+
+// GetServices Returns the extra containers declared in the site's local config.
+func (s *Settings) GetServices() map[string]Service {
+	if s.services == nil {
+		return map[string]Service{}
+	}
+
+	return s.services
+}
+
+// loadServices Reads the services section of a local config file.
+func (s *Settings) loadServices(ko Koanf) error {
+	if !ko.Exists("services") {
+		return nil
+	}
+
+	services := map[string]Service{}
+
+	err := ko.Unmarshal("services", &services)
+	if err != nil {
+		return fmt.Errorf("the services in your configuration are invalid: %s", err)
+	}
+
+	return s.setServices(services)
+}
+
+// setServicesFromJSON Sets the services from decoded JSON, such as the settings restored from an archive.
+func (s *Settings) setServicesFromJSON(value interface{}) error {
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	services := map[string]Service{}
+
+	err = json.Unmarshal(jsonBytes, &services)
+	if err != nil {
+		return fmt.Errorf("the services in your configuration are invalid: %s", err)
+	}
+
+	return s.setServices(services)
+}
+
+func (s *Settings) setServices(services map[string]Service) error {
+	for name := range services {
+		service := services[name]
+
+		err := validateService(name, &service)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.services = services
+
+	return nil
+}
+
+func validateService(name string, service *Service) error {
+	if !serviceNamePattern.MatchString(name) {
+		return fmt.Errorf(
+			"the service name, %s, is invalid. Service names may only contain lowercase letters, numbers and dashes",
+			name)
+	}
+
+	for _, reservedName := range reservedServiceNames {
+		if name == reservedName {
+			return fmt.Errorf("the service name, %s, is used by Kana. Please choose another name", name)
+		}
+	}
+
+	if service.Image == "" {
+		return fmt.Errorf("the service %s must have an image", name)
+	}
+
+	for _, volume := range service.Volumes {
+		if strings.Count(volume, ":") != 1 {
+			return fmt.Errorf("the volume %s for the service %s must be in the format <local path>:<container path>", volume, name)
+		}
+	}
+
+	return nil
+}
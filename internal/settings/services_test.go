@@ -0,0 +1,64 @@
+package settings
+
+import (
+	"testing"
+)
+
+func TestValidateService(t *testing.T) {
+	tests := []struct {
+		name    string
+		service Service
+		wantErr bool
+	}{
+		{"cache", Service{Image: "redis:7"}, false},
+		{"search-1", Service{Image: "elasticsearch:8.15.0", Volumes: []string{"./data:/usr/share/elasticsearch/data"}}, false},
+		{"Redis", Service{Image: "redis:7"}, true},
+		{"my.redis", Service{Image: "redis:7"}, true},
+		{"database", Service{Image: "redis:7"}, true},
+		{"redis", Service{Image: "redis:7"}, true},
+		{"cache", Service{}, true},
+		{"cache", Service{Image: "redis:7", Volumes: []string{"/data"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateService(tt.name, &tt.service)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateService() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetServicesFromJSON(t *testing.T) {
+	s := new(Settings)
+
+	if len(s.GetServices()) != 0 {
+		t.Errorf("Expected no services, got %d", len(s.GetServices()))
+	}
+
+	err := s.setServicesFromJSON(map[string]interface{}{
+		"cache": map[string]interface{}{
+			"image":    "redis:7",
+			"ports":    []interface{}{"6379"},
+			"httpPort": "8001",
+		},
+	})
+	if err != nil {
+		t.Fatalf("setServicesFromJSON returned an error: %v", err)
+	}
+
+	cache, ok := s.GetServices()["cache"]
+	if !ok {
+		t.Fatal("Expected the cache service to be set")
+	}
+
+	if cache.Image != "redis:7" || cache.HTTPPort != "8001" || len(cache.Ports) != 1 {
+		t.Errorf("Unexpected service: %+v", cache)
+	}
+
+	allSettings := s.GetAll("local")
+	if _, ok := allSettings["services"]; !ok {
+		t.Error("Expected the services to be included in the local settings")
+	}
+}
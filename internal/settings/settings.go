@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -43,6 +44,23 @@ func Load(kanaSettings *Settings, version string, cmd *cobra.Command) error {
 		return err
 	}
 
+	// A directory mounted into another site uses that site and its settings
+	if !settings["isNamed"].(bool) && settings["isNew"].(bool) {
+		name, link, found := findMountingSite(settings["workingDirectory"].(string), settings["appDirectory"].(string))
+		if found {
+			settings["name"] = name
+			settings["siteDirectory"] = filepath.Join(settings["appDirectory"].(string), "sites", name)
+			settings["isNew"] = false
+
+			// Named sites aren't linked to a project so they keep the current directory
+			if link == settings["siteDirectory"] {
+				settings["isNamed"] = true
+			} else {
+				settings["workingDirectory"] = link
+			}
+		}
+	}
+
 	for key, value := range settings {
 		err = kanaSettings.Set(key, value)
 		if err != nil {
@@ -183,6 +201,15 @@ func (s *Settings) Get(name string) string {
 	return ""
 }
 
+// GetDefaultDatabaseVersion Returns the default image version for the given database server.
+func GetDefaultDatabaseVersion(database string) string {
+	if database == "mysql" {
+		return mysqlVersion
+	}
+
+	return mariadbVersion
+}
+
 func (s *Settings) GetAll(settingsType string) map[string]interface{} {
 	allSettings := make(map[string]interface{})
 	koSettings := s.global
@@ -228,6 +255,14 @@ func (s *Settings) GetAll(settingsType string) map[string]interface{} {
 		}
 	}
 
+	if settingsType == "local" && len(s.services) > 0 {
+		allSettings["services"] = s.services
+	}
+
+	if settingsType == "local" && len(s.phpIni) > 0 {
+		allSettings["phpIni"] = s.phpIni
+	}
+
 	return allSettings
 }
 
@@ -309,6 +344,48 @@ func (s *Settings) Set(name string, value interface{}, setVars ...bool) error {
 	return fmt.Errorf("invalid setting %s. Please enter a valid key to set", name)
 }
 
+// LoadLocalSettings Applies a set of local settings, such as those restored from an archive, to the current settings.
+func (s *Settings) LoadLocalSettings(localSettings map[string]interface{}) error {
+	if services, ok := localSettings["services"]; ok {
+		err := s.setServicesFromJSON(services)
+		if err != nil {
+			return err
+		}
+	}
+
+	if phpIni, ok := localSettings["phpIni"]; ok {
+		err := s.setPHPIniFromJSON(phpIni)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i := range s.settings {
+		value, ok := localSettings[s.settings[i].name]
+		if !ok || !s.settings[i].hasLocal {
+			continue
+		}
+
+		// JSON arrays are decoded as []interface{} but our slice settings need []string
+		if rawSlice, isSlice := value.([]interface{}); isSlice {
+			sliceValue := make([]string, 0, len(rawSlice))
+
+			for _, item := range rawSlice {
+				sliceValue = append(sliceValue, fmt.Sprint(item))
+			}
+
+			value = sliceValue
+		}
+
+		err := s.Set(s.settings[i].name, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (s *Settings) WriteLocalSettings(localSettings map[string]interface{}) error {
 	configFile := filepath.Join(s.Get("workingDirectory"), ".kana.json")
 
@@ -377,6 +454,50 @@ func (s *Settings) validate(name string, value interface{}) error {
 					"the database version in your configuration, %s, is invalid. See %s for a list of supported versions",
 					stringVal, databaseURL)
 			}
+		case "composerVersion":
+			if docker.ValidateImage("composer", stringVal) != nil {
+				return fmt.Errorf(
+					"the Composer version in your configuration, %s, is invalid. See https://hub.docker.com/_/composer for a list of supported versions",
+					stringVal)
+			}
+		case "nodeVersion":
+			if docker.ValidateImage("node", stringVal) != nil {
+				return fmt.Errorf(
+					"the Node version in your configuration, %s, is invalid. See https://hub.docker.com/_/node for a list of supported versions",
+					stringVal)
+			}
+		case "dnsPort":
+			return validateIntRange(name, stringVal, 1, maxPort)
+		case "domains":
+			return validateDomains(value)
+		case "mounts":
+			return validateMounts(value)
+		case "phpExtensions":
+			return validatePHPExtensions(value)
+		case "plugins":
+			return validateExtensions(value)
+		case "theme":
+			if stringVal == "" {
+				return nil
+			}
+
+			_, err := ParseExtension(stringVal)
+
+			return err
+		case "xdebugClientHost":
+			if validate.Var(stringVal, "hostname_rfc1123|ip") != nil {
+				return fmt.Errorf("the Xdebug client host, %s, must be a hostname or IP address", stringVal)
+			}
+		case "xdebugIdeKey":
+			if !xdebugIdeKeyPattern.MatchString(stringVal) {
+				return fmt.Errorf("the Xdebug IDE key, %s, may only contain letters, numbers, dashes, dots and underscores", stringVal)
+			}
+		case "xdebugMode":
+			return ValidateXdebugMode(stringVal)
+		case "healthCheckTimeout":
+			return validateIntRange(name, stringVal, 1, math.MaxInt32)
+		case "xdebugPort":
+			return validateIntRange(name, stringVal, 1, maxPort)
 		case "php":
 			if docker.ValidateImage("wordpress", fmt.Sprintf("php%s", stringVal)) != nil {
 				return fmt.Errorf(
@@ -389,6 +510,16 @@ func (s *Settings) validate(name string, value interface{}) error {
 	return nil
 }
 
+// validateIntRange Checks an integer setting is between the given minimum and maximum, inclusive.
+func validateIntRange(name, value string, minValue, maxValue int) error {
+	intValue, err := strconv.Atoi(value)
+	if err != nil || intValue < minValue || intValue > maxValue {
+		return fmt.Errorf("the value for %s must be a whole number from %d to %d", name, minValue, maxValue)
+	}
+
+	return nil
+}
+
 func getSiteInfo(workingDirectory, appDirectory string, cmd *cobra.Command) (name, siteDirectory string, isNamed, isNew bool, err error) {
 	name = helpers.SanitizeSiteName(filepath.Base(workingDirectory))
 	isStartCommand := cmd.Use == "start"
@@ -450,6 +581,19 @@ func getStaticDirectories() (app, working string, err error) {
 }
 
 func saveLocalLinkConfig(cmd *cobra.Command, siteDirectory, workingDirectory string, isNamedSite bool) error {
+	linkConfigFile := filepath.Join(siteDirectory, "link.json")
+
+	_, err := os.Stat(linkConfigFile)
+
+	if err != nil && os.IsNotExist(err) && cmd.Use == "start" {
+		return WriteLinkConfig(siteDirectory, workingDirectory, isNamedSite)
+	}
+
+	return nil
+}
+
+// WriteLinkConfig Links the site's folder in the config directory to the working directory it belongs to.
+func WriteLinkConfig(siteDirectory, workingDirectory string, isNamedSite bool) error {
 	siteLink := map[string]string{
 		"link": workingDirectory}
 
@@ -459,27 +603,20 @@ func saveLocalLinkConfig(cmd *cobra.Command, siteDirectory, workingDirectory str
 
 	linkConfigFile := filepath.Join(siteDirectory, "link.json")
 
-	_, err := os.Stat(linkConfigFile)
-
-	if err != nil && os.IsNotExist(err) && cmd.Use == "start" {
-		err := os.MkdirAll(filepath.Dir(linkConfigFile), defaultDirPermissions)
-		if err != nil {
-			return err
-		}
-
-		f, _ := os.Create(linkConfigFile)
-		defer f.Close()
+	err := os.MkdirAll(filepath.Dir(linkConfigFile), defaultDirPermissions)
+	if err != nil {
+		return err
+	}
 
-		jsonBytes, err := json.MarshalIndent(siteLink, "", "\t")
-		if err != nil {
-			return err
-		}
+	f, _ := os.Create(linkConfigFile)
+	defer f.Close()
 
-		_, err = f.Write(jsonBytes)
-		if err != nil {
-			return err
-		}
+	jsonBytes, err := json.MarshalIndent(siteLink, "", "\t")
+	if err != nil {
+		return err
 	}
 
-	return nil
+	_, err = f.Write(jsonBytes)
+
+	return err
 }
@@ -21,6 +21,25 @@ func TestSettings_Get(t *testing.T) {
 		})
 	}
 }
+func TestGetDefaultDatabaseVersion(t *testing.T) {
+	tests := []struct {
+		database string
+		expected string
+	}{
+		{"mariadb", mariadbVersion},
+		{"mysql", mysqlVersion},
+		{"sqlite", mariadbVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.database, func(t *testing.T) {
+			got := GetDefaultDatabaseVersion(tt.database)
+			if got != tt.expected {
+				t.Errorf("Got %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
 func TestSettings_GetBool(t *testing.T) {
 	s := &Settings{
 		settings: []Setting{
@@ -49,3 +68,60 @@ func TestSettings_GetBool(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateIntRange(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"0", true},
+		{"1", false},
+		{"9003", false},
+		{"65535", false},
+		{"65536", true},
+		{"99999999", true},
+		{"-5", true},
+		{"port", true},
+		{"", true},
+	}
+
+	s := &Settings{settings: []Setting{
+		{name: "xdebugPort", settingType: "int"},
+		{name: "dnsPort", settingType: "int"},
+	}}
+
+	for _, name := range []string{"xdebugPort", "dnsPort"} {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.value, func(t *testing.T) {
+				err := s.validate(name, tt.value)
+				if (err != nil) != tt.wantErr {
+					t.Errorf("validate(%s, %q) error = %v, wantErr %v", name, tt.value, err, tt.wantErr)
+				}
+			})
+		}
+	}
+}
+
+func TestValidateHealthCheckTimeout(t *testing.T) {
+	tests := []struct {
+		value   string
+		wantErr bool
+	}{
+		{"-5", true},
+		{"0", true},
+		{"1", false},
+		{"120", false},
+		{"soon", true},
+	}
+
+	s := &Settings{settings: []Setting{{name: "healthCheckTimeout", settingType: "int"}}}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			err := s.validate("healthCheckTimeout", tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validate(healthCheckTimeout, %q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,10 +2,12 @@ package settings
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/ChrisWiegman/kana/internal/console"
 	"github.com/ChrisWiegman/kana/pkg/minica"
@@ -13,92 +15,119 @@ import (
 
 var execCommand = exec.Command
 
-// EnsureSSLCerts Ensures SSL certificates have been generated and are where they need to be.
-func EnsureSSLCerts(appDirectory string, useSSL bool, consoleOutput *console.Console) error {
-	createCert := false
-
+// EnsureSSLCerts Ensures SSL certificates have been generated and are where they need to be, renewing the shared
+// *.sites.kana.sh certificate when it is close to expiring. Returns true if the shared certificate changed.
+func EnsureSSLCerts(appDirectory string, useSSL bool, consoleOutput *console.Console) (bool, error) {
 	certPath := filepath.Join(appDirectory, "certs")
-	rootCertFile := filepath.Join(certPath, rootCert)
 
-	_, err := os.Stat(rootCertFile)
-	if err != nil && os.IsNotExist(err) {
-		createCert = true
+	err := os.MkdirAll(certPath, os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return false, err
+	}
+
+	certInfo := minica.CertInfo{
+		CertDir:    certPath,
+		CertDomain: domain,
+		RootKey:    rootKey,
+		RootCert:   rootCert,
+		SiteCert:   siteCert,
+		SiteKey:    siteKey,
 	}
 
-	if createCert {
-		err = os.MkdirAll(certPath, os.FileMode(defaultDirPermissions))
+	// The root certificate is created along with the first certificate it signs
+	changed, err := minica.EnsureCert(&certInfo, []string{"*." + domain}, nil)
+	if err != nil {
+		return changed, err
+	}
+
+	if !useSSL {
+		return changed, nil
+	}
+
+	// If we're on Mac try to add the cert to the system trust.
+	if runtime.GOOS == "darwin" {
+		return changed, TrustSSL(rootCert, appDirectory, consoleOutput)
+	}
+
+	// Trusting the cert on Linux needs sudo and tools that may be missing so leave it to the trust-ssl command
+	if runtime.GOOS == "linux" {
+		err = VerifySSLTrust(rootCert, appDirectory)
 		if err != nil {
-			return err
+			consoleOutput.Warn(fmt.Sprintf("%s. Run 'kana trust-ssl' to trust it.", err))
 		}
+	}
+
+	return changed, nil
+}
 
-		certInfo := minica.CertInfo{
-			CertDir:    certPath,
-			CertDomain: domain,
-			RootKey:    rootKey,
-			RootCert:   rootCert,
-			SiteCert:   siteCert,
-			SiteKey:    siteKey,
+// EnsureSiteCert Makes sure a site has its own certificate covering the given domains, as well as the local IP
+// addresses, renewing it when it is close to expiring. Passing no domains removes the site's certificate. Returns true
+// if the certificate changed.
+func EnsureSiteCert(appDirectory, name string, domains []string) (bool, error) {
+	sitesCertPath := filepath.Join(appDirectory, "certs", "sites")
+
+	if len(domains) == 0 {
+		changed := false
+
+		for _, file := range []string{filepath.Join(sitesCertPath, name+".pem"), filepath.Join(sitesCertPath, name+".key")} {
+			err := os.Remove(file)
+			if err == nil {
+				changed = true
+			} else if !os.IsNotExist(err) {
+				return false, err
+			}
 		}
 
-		err = minica.GenCerts(&certInfo)
-		if err != nil {
-			return err
+		if !changed {
+			return false, nil
 		}
+
+		return true, ensureStaticConfigFiles(appDirectory)
 	}
 
-	// If we're on Mac try to add the cert to the system trust.
-	if useSSL && runtime.GOOS == certOS {
-		return TrustSSL(rootCert, appDirectory, consoleOutput)
+	err := os.MkdirAll(sitesCertPath, os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return false, err
 	}
 
-	return nil
-}
+	certInfo := minica.CertInfo{
+		CertDir:  filepath.Join(appDirectory, "certs"),
+		RootKey:  rootKey,
+		RootCert: rootCert,
+		SiteCert: filepath.Join("sites", name+".pem"),
+		SiteKey:  filepath.Join("sites", name+".key"),
+	}
 
-// TrustSSL Adds the Kana certificate to the Apple Keychain.
-func TrustSSL(rootCert, appDirectory string, consoleOutput *console.Console) error {
-	if runtime.GOOS != certOS {
-		return fmt.Errorf("the trust command is only available for MacOS")
+	changed, err := minica.EnsureCert(&certInfo, domains, []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback})
+	if err != nil || !changed {
+		return changed, err
 	}
 
-	err := VerifySSLTrust()
+	return true, ensureStaticConfigFiles(appDirectory)
+}
+
+// getCertDomains Returns the domains covered by a certificate file.
+func getCertDomains(certFile string) ([]string, error) {
+	cert, err := readCertFile(certFile)
 	if err != nil {
-		consoleOutput.Println("Adding Kana's SSL certificate to your system keychain. You will be promoted for your password.")
-
-		certPath := filepath.Join(appDirectory, "certs")
-		rootCertFile := filepath.Join(certPath, rootCert)
-
-		installCertCommand := execCommand(
-			"sudo",
-			"security",
-			"add-trusted-cert",
-			"-d",
-			"-r",
-			"trustRoot",
-			"-k",
-			"/Library/Keychains/System.keychain",
-			rootCertFile)
-
-		err = installCertCommand.Run()
-		if err != nil {
-			return err
-		}
+		return nil, err
 	}
 
-	return nil
+	return cert.DNSNames, nil
 }
 
-// VerifySSLTrust verifies the SSL certificate has been added to that Apple Keychain.
-func VerifySSLTrust() error {
-	if runtime.GOOS == certOS {
-		verifyCertCommand := execCommand(
-			"security",
-			"find-certificate",
-			"-c",
-			"Kana Development CA",
-			"/Library/Keychains/System.keychain")
-
-		return verifyCertCommand.Run()
+// getSiteCertNames Returns the names of the sites that have their own certificate.
+func getSiteCertNames(appDirectory string) ([]string, error) {
+	certFiles, err := filepath.Glob(filepath.Join(appDirectory, "certs", "sites", "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+
+	for _, certFile := range certFiles {
+		names = append(names, strings.TrimSuffix(filepath.Base(certFile), ".pem"))
 	}
 
-	return fmt.Errorf("the trust command is only available for MacOS")
+	return names, nil
 }
@@ -0,0 +1,19 @@
+<?php
+/**
+ * Plugin Name: Kana Mount Loader: {{ .Slug }}
+ * Plugin URI: https://github.com/ChrisWiegman/kana
+ * Description: Loads the must-use plugins in the {{ .Slug }} folder mounted by Kana.
+ * Author: Chris Wiegman
+ *
+ * @package KanaCLI
+ **/
+
+/*
+ * WordPress only loads must-use plugins at the top of the mu-plugins folder
+ * so load every file in the mounted folder with a plugin header.
+ */
+foreach ( glob( __DIR__ . '/{{ .Slug }}/*.php' ) as $kana_mu_plugin ) {
+	if ( false !== strpos( (string) file_get_contents( $kana_mu_plugin, false, null, 0, 8192 ), 'Plugin Name:' ) ) {
+		require_once $kana_mu_plugin;
+	}
+}
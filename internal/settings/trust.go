@@ -0,0 +1,446 @@
+package settings
+
+import (
+	"bytes"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	caName        = "Kana Development CA"
+	macOSKeychain = "/Library/Keychains/System.keychain"
+	p11KitObject  = "[p11-kit-object-v1]"
+)
+
+// The PKCS #11 URI matching every anchor Kana has added to p11-kit, whichever root certificate it came from.
+var trustAnchorURI = "pkcs11:object=" + url.PathEscape(caName) + ";type=cert"
+
+var lookPath = exec.LookPath
+
+// isDirectory Returns true if the path is an existing directory.
+var isDirectory = func(path string) bool {
+	info, err := os.Stat(path)
+
+	return err == nil && info.IsDir()
+}
+
+// linuxTrustStore is a way of adding certificates to the system trust of a Linux distribution.
+type linuxTrustStore struct {
+	command  string
+	certFile string
+	update   []string
+}
+
+// The system trust stores of the Linux distributions Kana supports, in the order they are looked for. A store without
+// a certFile is managed with p11-kit's trust command. Arch ships update-ca-trust without the Fedora anchors directory so
+// it falls through to trust.
+var linuxTrustStores = []linuxTrustStore{
+	{
+		command:  "update-ca-certificates",
+		certFile: "/usr/local/share/ca-certificates/kana-root.crt",
+		update:   []string{"update-ca-certificates"},
+	},
+	{
+		command:  "update-ca-trust",
+		certFile: "/etc/pki/ca-trust/source/anchors/kana-root.pem",
+		update:   []string{"update-ca-trust", "extract"},
+	},
+	{
+		command: "trust",
+	},
+}
+
+// The NSS databases, relative to the home directory, used by Chrome, Chromium and Firefox on Linux.
+var nssDatabasePatterns = []string{
+	".pki/nssdb",
+	"snap/chromium/current/.pki/nssdb",
+	".mozilla/firefox/*",
+	"snap/firefox/common/.mozilla/firefox/*",
+	".var/app/org.mozilla.firefox/.mozilla/firefox/*",
+}
+
+// TrustSSL Adds the Kana root certificate to the system trust and, on Linux, the certificate databases of Chrome and
+// Firefox.
+func TrustSSL(rootCert, appDirectory string, consoleOutput *console.Console) error {
+	if !isTrustSupported() {
+		return fmt.Errorf("the trust command is only available on MacOS and Linux")
+	}
+
+	rootCertFile := filepath.Join(appDirectory, "certs", rootCert)
+
+	if runtime.GOOS == "darwin" {
+		if isMacOSTrusted() {
+			return nil
+		}
+
+		consoleOutput.Println("Adding Kana's SSL certificate to your system keychain. You will be prompted for your password.")
+
+		return execCommand("sudo", "security", "add-trusted-cert", "-d", "-r", "trustRoot", "-k", macOSKeychain, rootCertFile).Run()
+	}
+
+	store, err := getLinuxTrustStore()
+	if err != nil {
+		return err
+	}
+
+	if !isLinuxSystemTrusted(store, rootCertFile) {
+		consoleOutput.Println("Adding Kana's SSL certificate to your system's trusted certificates. You may be prompted for your password.")
+
+		err = trustLinuxSystem(store, rootCertFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	return trustNSSDatabases(rootCertFile, consoleOutput)
+}
+
+// UntrustSSL Removes the Kana root certificate from everywhere TrustSSL adds it.
+func UntrustSSL(rootCert, appDirectory string, consoleOutput *console.Console) error {
+	if !isTrustSupported() {
+		return fmt.Errorf("the trust command is only available on MacOS and Linux")
+	}
+
+	rootCertFile := filepath.Join(appDirectory, "certs", rootCert)
+
+	if runtime.GOOS == "darwin" {
+		if !isMacOSTrusted() {
+			return nil
+		}
+
+		consoleOutput.Println("Removing Kana's SSL certificate from your system keychain. You will be prompted for your password.")
+
+		return execCommand("sudo", "security", "delete-certificate", "-t", "-c", caName, macOSKeychain).Run()
+	}
+
+	if hasCertutil() {
+		for _, nssDatabase := range getNSSDatabases(getHomeDirectory()) {
+			if !isNSSDatabaseTrusted(nssDatabase, rootCertFile) {
+				continue
+			}
+
+			err := runCertutil(nssDatabase, "-D", "-n", caName)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	store, err := getLinuxTrustStore()
+	if err != nil {
+		return err
+	}
+
+	if store.certFile == "" {
+		// Remove any anchor with Kana's label so those left by older root certificates go too
+		if len(getTrustAnchors()) == 0 {
+			return nil
+		}
+
+		consoleOutput.Println("Removing Kana's SSL certificate from your system's trusted certificates. You may be prompted for your password.")
+
+		return runSudo(nil, "trust", "anchor", "--remove", trustAnchorURI)
+	}
+
+	if !isLinuxSystemTrusted(store, rootCertFile) {
+		return nil
+	}
+
+	consoleOutput.Println("Removing Kana's SSL certificate from your system's trusted certificates. You may be prompted for your password.")
+
+	err = runSudo(nil, "rm", "-f", store.certFile)
+	if err != nil {
+		return err
+	}
+
+	return runSudo(nil, store.update...)
+}
+
+// VerifySSLTrust Verifies the Kana root certificate has been added to the system trust and, on Linux, to the
+// certificate databases of Chrome and Firefox.
+func VerifySSLTrust(rootCert, appDirectory string) error {
+	if !isTrustSupported() {
+		return fmt.Errorf("the trust command is only available on MacOS and Linux")
+	}
+
+	if runtime.GOOS == "darwin" {
+		if !isMacOSTrusted() {
+			return fmt.Errorf("the Kana SSL certificate is not in your system keychain")
+		}
+
+		return nil
+	}
+
+	rootCertFile := filepath.Join(appDirectory, "certs", rootCert)
+	untrusted := []string{}
+
+	store, err := getLinuxTrustStore()
+	if err != nil {
+		return err
+	}
+
+	if !isLinuxSystemTrusted(store, rootCertFile) {
+		untrusted = append(untrusted, "your system")
+	}
+
+	if hasCertutil() {
+		for _, nssDatabase := range getNSSDatabases(getHomeDirectory()) {
+			if !isNSSDatabaseTrusted(nssDatabase, rootCertFile) {
+				untrusted = append(untrusted, nssDatabase)
+			}
+		}
+	}
+
+	if len(untrusted) > 0 {
+		return fmt.Errorf("the Kana SSL certificate is not trusted by %s", strings.Join(untrusted, ", "))
+	}
+
+	return nil
+}
+
+// isTrustSupported Returns true if Kana knows how to trust its certificate on the current system.
+func isTrustSupported() bool {
+	return runtime.GOOS == "darwin" || runtime.GOOS == "linux"
+}
+
+// isMacOSTrusted Returns true if the Kana root certificate is in the MacOS system keychain.
+func isMacOSTrusted() bool {
+	return execCommand("security", "find-certificate", "-c", caName, macOSKeychain).Run() == nil
+}
+
+// getLinuxTrustStore Returns the first system trust store whose tools are installed and, for stores that take a
+// certificate file, whose anchors directory exists.
+func getLinuxTrustStore() (linuxTrustStore, error) {
+	for _, store := range linuxTrustStores {
+		if _, err := lookPath(store.command); err != nil {
+			continue
+		}
+
+		if store.certFile != "" && !isDirectory(filepath.Dir(store.certFile)) {
+			continue
+		}
+
+		return store, nil
+	}
+
+	return linuxTrustStore{}, fmt.Errorf(
+		"unable to find update-ca-certificates, update-ca-trust or trust to add the Kana SSL certificate to your system")
+}
+
+// isLinuxSystemTrusted Returns true if the current Kana root certificate is in the system trust store.
+func isLinuxSystemTrusted(store linuxTrustStore, rootCertFile string) bool {
+	if store.certFile != "" {
+		return isSameCertFile(store.certFile, rootCertFile)
+	}
+
+	rootCertContents, err := os.ReadFile(rootCertFile)
+	if err != nil {
+		return false
+	}
+
+	for _, anchor := range getTrustAnchors() {
+		if isSameCert(anchor, rootCertContents) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getTrustAnchors Returns the PEM encoded certificates of the p11-kit anchors with Kana's label.
+func getTrustAnchors() [][]byte {
+	output, err := execCommand("trust", "dump", "--filter=ca-anchors").Output()
+	if err != nil {
+		return [][]byte{}
+	}
+
+	return parseTrustAnchors(output)
+}
+
+// parseTrustAnchors Returns the PEM encoded certificates of the objects with Kana's label in the output of trust dump.
+func parseTrustAnchors(output []byte) [][]byte {
+	anchors := [][]byte{}
+	label := fmt.Sprintf("label: %q", caName)
+
+	for _, object := range strings.Split(strings.ReplaceAll(string(output), "\r\n", "\n"), p11KitObject) {
+		hasLabel := false
+
+		for _, line := range strings.Split(object, "\n") {
+			if strings.TrimSpace(line) == label {
+				hasLabel = true
+				break
+			}
+		}
+
+		if !hasLabel {
+			continue
+		}
+
+		block, _ := pem.Decode([]byte(object))
+		if block == nil {
+			continue
+		}
+
+		anchors = append(anchors, pem.EncodeToMemory(block))
+	}
+
+	return anchors
+}
+
+// trustLinuxSystem Adds the Kana root certificate to the system trust store.
+func trustLinuxSystem(store linuxTrustStore, rootCertFile string) error {
+	if store.certFile == "" {
+		// Replace any anchor left behind by an older Kana root certificate
+		if len(getTrustAnchors()) > 0 {
+			err := runSudo(nil, "trust", "anchor", "--remove", trustAnchorURI)
+			if err != nil {
+				return err
+			}
+		}
+
+		return runSudo(nil, "trust", "anchor", "--store", rootCertFile)
+	}
+
+	err := runSudo(nil, "install", "-m", "0644", rootCertFile, store.certFile)
+	if err != nil {
+		return err
+	}
+
+	return runSudo(nil, store.update...)
+}
+
+// trustNSSDatabases Adds the Kana root certificate to each Chrome and Firefox certificate database that doesn't have it.
+func trustNSSDatabases(rootCertFile string, consoleOutput *console.Console) error {
+	nssDatabases := getNSSDatabases(getHomeDirectory())
+
+	if len(nssDatabases) == 0 {
+		return nil
+	}
+
+	if !hasCertutil() {
+		consoleOutput.Warn(
+			"Install certutil, from libnss3-tools or nss-tools, and run `kana trust-ssl` again for Chrome and Firefox " +
+				"to trust Kana's SSL certificate.")
+
+		return nil
+	}
+
+	for _, nssDatabase := range nssDatabases {
+		if isNSSDatabaseTrusted(nssDatabase, rootCertFile) {
+			continue
+		}
+
+		// Replace any certificate left behind by an older Kana root certificate
+		_ = runCertutil(nssDatabase, "-D", "-n", caName)
+
+		err := runCertutil(nssDatabase, "-A", "-t", "C,,", "-n", caName, "-i", rootCertFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getNSSDatabases Returns the NSS certificate databases in the given home directory.
+func getNSSDatabases(homeDirectory string) []string {
+	nssDatabases := []string{}
+
+	if homeDirectory == "" {
+		return nssDatabases
+	}
+
+	for _, pattern := range nssDatabasePatterns {
+		matches, err := filepath.Glob(filepath.Join(homeDirectory, pattern))
+		if err != nil {
+			continue
+		}
+
+		for _, match := range matches {
+			if _, err := os.Stat(filepath.Join(match, "cert9.db")); err == nil {
+				nssDatabases = append(nssDatabases, match)
+			}
+		}
+	}
+
+	return nssDatabases
+}
+
+// isNSSDatabaseTrusted Returns true if an NSS database has the current Kana root certificate.
+func isNSSDatabaseTrusted(nssDatabase, rootCertFile string) bool {
+	output, err := execCommand("certutil", "-d", "sql:"+nssDatabase, "-L", "-n", caName, "-a").Output()
+	if err != nil {
+		return false
+	}
+
+	rootCertContents, err := os.ReadFile(rootCertFile)
+	if err != nil {
+		return false
+	}
+
+	return isSameCert(output, rootCertContents)
+}
+
+// runCertutil Runs certutil against an NSS database, returning its output as the error if it fails.
+func runCertutil(nssDatabase string, args ...string) error {
+	output, err := execCommand("certutil", append([]string{"-d", "sql:" + nssDatabase}, args...)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("unable to update the certificates in %s: %s", nssDatabase, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+// hasCertutil Returns true if the NSS certutil command is installed.
+func hasCertutil() bool {
+	_, err := lookPath("certutil")
+
+	return err == nil
+}
+
+// getHomeDirectory Returns the user's home directory or an empty string if it can't be found.
+func getHomeDirectory() string {
+	home, err := homedir.Dir()
+	if err != nil {
+		return ""
+	}
+
+	return home
+}
+
+// isSameCertFile Returns true if two files hold the same certificate.
+func isSameCertFile(certFile, otherCertFile string) bool {
+	certContents, err := os.ReadFile(certFile)
+	if err != nil {
+		return false
+	}
+
+	otherCertContents, err := os.ReadFile(otherCertFile)
+	if err != nil {
+		return false
+	}
+
+	return isSameCert(certContents, otherCertContents)
+}
+
+// isSameCert Returns true if two PEM encoded certificates are the same certificate.
+func isSameCert(certContents, otherCertContents []byte) bool {
+	block, _ := pem.Decode(certContents)
+	otherBlock, _ := pem.Decode(otherCertContents)
+
+	if block == nil || otherBlock == nil {
+		return false
+	}
+
+	return bytes.Equal(block.Bytes, otherBlock.Bytes)
+}
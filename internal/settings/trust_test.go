@@ -0,0 +1,173 @@
+package settings
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNSSDatabases(t *testing.T) {
+	homeDirectory := t.TempDir()
+
+	for _, nssDatabase := range []string{".pki/nssdb", ".mozilla/firefox/abc123.default-release", ".mozilla/firefox/empty"} {
+		err := os.MkdirAll(filepath.Join(homeDirectory, nssDatabase), os.FileMode(defaultDirPermissions))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, nssDatabase := range []string{".pki/nssdb", ".mozilla/firefox/abc123.default-release"} {
+		err := os.WriteFile(filepath.Join(homeDirectory, nssDatabase, "cert9.db"), []byte{}, os.FileMode(defaultFilePermissions))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assert.Equal(t, []string{
+		filepath.Join(homeDirectory, ".pki/nssdb"),
+		filepath.Join(homeDirectory, ".mozilla/firefox/abc123.default-release"),
+	}, getNSSDatabases(homeDirectory))
+
+	assert.Empty(t, getNSSDatabases(""))
+}
+
+func TestGetLinuxTrustStore(t *testing.T) {
+	defaultIsDirectory := isDirectory
+
+	defer func() {
+		lookPath = exec.LookPath
+		isDirectory = defaultIsDirectory
+	}()
+
+	debianAnchors := "/usr/local/share/ca-certificates"
+	fedoraAnchors := "/etc/pki/ca-trust/source/anchors"
+	archAnchors := "/etc/ca-certificates/trust-source/anchors"
+
+	var tests = []struct {
+		name          string
+		commands      []string
+		directories   []string
+		expectedStore string
+		wantErr       bool
+	}{
+		{"Debian and Ubuntu", []string{"update-ca-certificates", "trust"}, []string{debianAnchors}, "update-ca-certificates", false},
+		{"Fedora", []string{"update-ca-trust", "trust"}, []string{fedoraAnchors}, "update-ca-trust", false},
+		{"Arch", []string{"update-ca-trust", "trust"}, []string{archAnchors}, "trust", false},
+		{"Only p11-kit", []string{"trust"}, []string{}, "trust", false},
+		{"update-ca-trust without its anchors", []string{"update-ca-trust"}, []string{archAnchors}, "", true},
+		{"No trust tools", []string{}, []string{debianAnchors, fedoraAnchors}, "", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := t.TempDir()
+
+			for _, directory := range test.directories {
+				err := os.MkdirAll(filepath.Join(root, directory), os.FileMode(defaultDirPermissions))
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			lookPath = func(file string) (string, error) {
+				for _, command := range test.commands {
+					if command == file {
+						return "/usr/bin/" + file, nil
+					}
+				}
+
+				return "", fmt.Errorf("%s not found", file)
+			}
+
+			isDirectory = func(path string) bool {
+				info, err := os.Stat(filepath.Join(root, path))
+
+				return err == nil && info.IsDir()
+			}
+
+			store, err := getLinuxTrustStore()
+			assert.Equal(t, test.wantErr, err != nil)
+			assert.Equal(t, test.expectedStore, store.command)
+		})
+	}
+}
+
+func TestIsLinuxSystemTrusted(t *testing.T) {
+	appDirectory := t.TempDir()
+	otherAppDirectory := t.TempDir()
+
+	for _, directory := range []string{appDirectory, otherAppDirectory} {
+		_, err := EnsureSSLCerts(directory, false, new(console.Console))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rootCertFile := filepath.Join(appDirectory, "certs", rootCert)
+	store := linuxTrustStore{certFile: filepath.Join(t.TempDir(), "kana-root.crt")}
+
+	assert.False(t, isLinuxSystemTrusted(store, rootCertFile))
+
+	// A certificate left behind by another root certificate isn't trusted
+	store.certFile = filepath.Join(otherAppDirectory, "certs", rootCert)
+	assert.False(t, isLinuxSystemTrusted(store, rootCertFile))
+
+	store.certFile = rootCertFile
+	assert.True(t, isLinuxSystemTrusted(store, rootCertFile))
+}
+
+func TestParseTrustAnchors(t *testing.T) {
+	appDirectory := t.TempDir()
+	otherAppDirectory := t.TempDir()
+
+	for _, directory := range []string{appDirectory, otherAppDirectory} {
+		_, err := EnsureSSLCerts(directory, false, new(console.Console))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rootCertContents, err := os.ReadFile(filepath.Join(appDirectory, "certs", rootCert))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherRootCertContents, err := os.ReadFile(filepath.Join(otherAppDirectory, "certs", rootCert))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	anchor := func(label string, certContents []byte) string {
+		return fmt.Sprintf("%s\nlabel: %q\ntrusted: true\nnss-mozilla-ca-policy: false\nmodifiable: true\n%s\n",
+			p11KitObject, label, certContents)
+	}
+
+	tests := []struct {
+		name     string
+		output   string
+		expected int
+	}{
+		{"No anchors", "", 0},
+		{"Other anchors", anchor("ISRG Root X1", otherRootCertContents), 0},
+		{"Kana anchor", anchor("ISRG Root X1", otherRootCertContents) + anchor(caName, rootCertContents), 1},
+		{"Stale Kana anchors", anchor(caName, otherRootCertContents) + anchor(caName, rootCertContents), 2},
+		{"Kana label without a certificate", fmt.Sprintf("%s\nlabel: %q\n", p11KitObject, caName), 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			anchors := parseTrustAnchors([]byte(test.output))
+			assert.Len(t, anchors, test.expected)
+
+			if test.expected > 0 {
+				assert.True(t, isSameCert(anchors[len(anchors)-1], rootCertContents))
+			}
+		})
+	}
+}
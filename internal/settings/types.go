@@ -14,6 +14,12 @@ type File struct {
 	Template    string
 }
 
+// ConfigFileData represents what Kana knows about all sites when writing the shared Traefik and DNS config files.
+type ConfigFileData struct {
+	SiteCerts []string
+	DNSZones  []string
+}
+
 // PluginVersion represents the name and version of a plugin to allow for better templating.
 type PluginVersion struct {
 	SiteName string
@@ -23,10 +29,22 @@ type PluginVersion struct {
 // A collection of all settings values used by Kana.
 type Settings struct {
 	settings []Setting
+	services map[string]Service
+	phpIni   map[string]string
 	global   Koanf
 	local    Koanf
 }
 
+// Service represents an extra container, such as Redis or Elasticsearch, declared in a site's local config.
+type Service struct {
+	Image    string   `json:"image" koanf:"image"`
+	Command  []string `json:"command,omitempty" koanf:"command"`
+	Env      []string `json:"env,omitempty" koanf:"env"`
+	Ports    []string `json:"ports,omitempty" koanf:"ports"`
+	Volumes  []string `json:"volumes,omitempty" koanf:"volumes"`
+	HTTPPort string   `json:"httpPort,omitempty" koanf:"httpPort"`
+}
+
 // An individual setting and its associated data.
 type Setting struct {
 	defaultValue string
@@ -55,4 +73,5 @@ type Koanf interface {
 	Strings(path string) []string
 	String(path string) string
 	Set(key string, val interface{}) error
+	Unmarshal(path string, o interface{}) error
 }
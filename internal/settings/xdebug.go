@@ -0,0 +1,42 @@
+package settings
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/helpers"
+)
+
+var xdebugIdeKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]*$`)
+
+// xdebugModes are the values Xdebug accepts for xdebug.mode, see https://xdebug.org/docs/all_settings#mode
+var xdebugModes = []string{
+	"coverage",
+	"debug",
+	"develop",
+	"gcstats",
+	"off",
+	"profile",
+	"trace",
+}
+
+// ValidateXdebugMode Checks a comma separated list of Xdebug modes.
+func ValidateXdebugMode(mode string) error {
+	modes := strings.Split(mode, ",")
+
+	for _, xdebugMode := range modes {
+		if !helpers.IsValidString(strings.TrimSpace(xdebugMode), xdebugModes) {
+			return fmt.Errorf(
+				"the Xdebug mode, %s, is invalid. Use a comma separated list of %s",
+				mode,
+				strings.Join(xdebugModes, ", "))
+		}
+
+		if strings.TrimSpace(xdebugMode) == "off" && len(modes) > 1 {
+			return fmt.Errorf("the Xdebug mode off can't be combined with other modes")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package settings
+
+import (
+	"testing"
+)
+
+func TestValidateXdebugMode(t *testing.T) {
+	tests := []struct {
+		mode    string
+		wantErr bool
+	}{
+		{"debug", false},
+		{"debug,develop", false},
+		{"profile", false},
+		{"debug, trace", false},
+		{"off", false},
+		{"off,debug", true},
+		{"step", true},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			err := ValidateXdebugMode(tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateXdebugMode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+	"github.com/ChrisWiegman/kana/internal/settings"
+)
+
+const (
+	archiveDatabaseFile = "database.sql"
+	archiveManifestFile = "archive.json"
+	archiveSettingsFile = "kana.json"
+	archiveLinkFile     = "link.json"
+)
+
+// CreateArchive Saves the site's wp-content folder, database and settings to a single portable archive.
+func (s *Site) CreateArchive(args []string, consoleOutput *console.Console) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	created := time.Now()
+	archiveFile := filepath.Join(cwd, fmt.Sprintf("kana-%s-%s.tar.gz", s.settings.Get("name"), created.Format("20060102-150405")))
+
+	if len(args) == 1 {
+		archiveFile = filepath.Join(cwd, args[0])
+	}
+
+	wordPressDirectory, err := s.getWordPressDirectory()
+	if err != nil {
+		return "", err
+	}
+
+	manifest, err := s.getSnapshotManifest(filepath.Base(archiveFile), created, consoleOutput)
+	if err != nil {
+		return "", err
+	}
+
+	localSettings, err := s.getRunningConfig(false, consoleOutput)
+	if err != nil {
+		return "", err
+	}
+
+	tempDirectory, err := os.MkdirTemp(s.settings.Get("siteDirectory"), "archive")
+	if err != nil {
+		return "", err
+	}
+
+	defer os.RemoveAll(tempDirectory)
+
+	err = writeJSONFile(filepath.Join(tempDirectory, archiveManifestFile), manifest)
+	if err != nil {
+		return "", err
+	}
+
+	err = writeJSONFile(filepath.Join(tempDirectory, archiveSettingsFile), localSettings)
+	if err != nil {
+		return "", err
+	}
+
+	entries := []helpers.ArchiveEntry{
+		{Name: archiveManifestFile, Path: filepath.Join(tempDirectory, archiveManifestFile)},
+		{Name: archiveSettingsFile, Path: filepath.Join(tempDirectory, archiveSettingsFile)},
+		{Name: archiveLinkFile, Path: filepath.Join(s.settings.Get("siteDirectory"), archiveLinkFile)},
+		{Name: "wp-content", Path: filepath.Join(wordPressDirectory, "wp-content")},
+	}
+
+	// SQLite databases are stored in wp-content so we only need to dump other databases
+	if manifest.Database != "sqlite" {
+		consoleOutput.Println("Exporting the database.")
+
+		exportFile := path.Join(filepath.Base(tempDirectory), archiveDatabaseFile)
+
+		err = s.exportDatabaseToSite(exportFile, consoleOutput)
+		if err != nil {
+			return "", err
+		}
+
+		entries = append(entries, helpers.ArchiveEntry{Name: archiveDatabaseFile, Path: filepath.Join(tempDirectory, archiveDatabaseFile)})
+	}
+
+	consoleOutput.Println("Writing the archive.")
+
+	return archiveFile, helpers.CreateArchive(archiveFile, entries)
+}
+
+// RestoreArchive Recreates a site from an archive, starting it and importing its database under the current name and domain.
+func (s *Site) RestoreArchive(file string, consoleOutput *console.Console) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	archiveFile := filepath.Join(cwd, file)
+	if filepath.IsAbs(file) {
+		archiveFile = file
+	}
+
+	err = os.MkdirAll(s.settings.Get("siteDirectory"), os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return err
+	}
+
+	tempDirectory, err := os.MkdirTemp(s.settings.Get("siteDirectory"), "archive")
+	if err != nil {
+		return err
+	}
+
+	defer os.RemoveAll(tempDirectory)
+
+	consoleOutput.Println("Extracting the archive.")
+
+	err = helpers.ExtractArchive(archiveFile, tempDirectory)
+	if err != nil {
+		return err
+	}
+
+	var manifest SnapshotManifest
+
+	err = readJSONFile(filepath.Join(tempDirectory, archiveManifestFile), &manifest)
+	if err != nil {
+		return fmt.Errorf("the file, %s, is not a valid Kana archive: %s", file, err)
+	}
+
+	err = s.restoreArchiveSettings(tempDirectory)
+	if err != nil {
+		return err
+	}
+
+	wordPressDirectory, err := s.getWordPressDirectory()
+	if err != nil {
+		return err
+	}
+
+	err = replaceDirectory(filepath.Join(tempDirectory, "wp-content"), filepath.Join(wordPressDirectory, "wp-content"))
+	if err != nil {
+		return err
+	}
+
+	err = s.startSite(consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	if manifest.Database == "sqlite" {
+		err = s.replaceDomain(manifest.Domain, consoleOutput)
+	} else {
+		err = os.Rename(
+			filepath.Join(tempDirectory, archiveDatabaseFile),
+			filepath.Join(s.settings.Get("siteDirectory"), "import.sql"))
+		if err != nil {
+			return err
+		}
+
+		err = s.importDatabaseFromSite("import.sql", false, manifest.Domain, consoleOutput)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return s.OpenSite(false, false, true, false, false, consoleOutput)
+}
+
+// replaceDirectory Replaces the destination with a copy of the source, keeping the original aside until the copy succeeds.
+func replaceDirectory(source, destination string) error {
+	backupPath := destination + "-backup"
+
+	err := os.RemoveAll(backupPath)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(destination, backupPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	err = helpers.CopyDirectory(source, destination)
+	if err != nil {
+		// Put the original back so a failed restore doesn't leave a mix of old and new files
+		removeErr := os.RemoveAll(destination)
+		if removeErr == nil {
+			removeErr = os.Rename(backupPath, destination)
+		}
+
+		if removeErr != nil && !os.IsNotExist(removeErr) {
+			return fmt.Errorf("%s. Your original files have been kept at %s", err, backupPath)
+		}
+
+		return err
+	}
+
+	return os.RemoveAll(backupPath)
+}
+
+// restoreArchiveSettings Applies the archived site settings and links the restored site to the current directory.
+func (s *Site) restoreArchiveSettings(archiveDirectory string) error {
+	localSettings := map[string]interface{}{}
+
+	err := readJSONFile(filepath.Join(archiveDirectory, archiveSettingsFile), &localSettings)
+	if err != nil {
+		return err
+	}
+
+	err = s.settings.LoadLocalSettings(localSettings)
+	if err != nil {
+		return err
+	}
+
+	err = settings.WriteLinkConfig(s.settings.Get("siteDirectory"), s.settings.Get("workingDirectory"), s.settings.GetBool("isNamed"))
+	if err != nil {
+		return err
+	}
+
+	// Named sites aren't linked to a directory so they have no local config file to write
+	if s.settings.GetBool("isNamed") {
+		return nil
+	}
+
+	return s.settings.WriteLocalSettings(localSettings)
+}
+
+func readJSONFile(file string, value interface{}) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(content, value)
+}
+
+func writeJSONFile(file string, value interface{}) error {
+	jsonBytes, err := json.MarshalIndent(value, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	_, filePerms := settings.GetDefaultFilePermissions()
+
+	return os.WriteFile(file, jsonBytes, os.FileMode(filePerms))
+}
@@ -0,0 +1,60 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaceDirectory(t *testing.T) {
+	var tests = []struct {
+		name          string
+		sourceExists  bool
+		expectedFiles []string
+		expectError   bool
+	}{
+		{
+			name:          "Archive copied",
+			sourceExists:  true,
+			expectedFiles: []string{"archived.php"},
+		},
+		{
+			name:          "Original kept on failure",
+			sourceExists:  false,
+			expectedFiles: []string{"original.php"},
+			expectError:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := t.TempDir()
+			source := filepath.Join(root, "archive", "wp-content")
+			destination := filepath.Join(root, "wordpress", "wp-content")
+
+			assert.NoError(t, os.MkdirAll(destination, 0750))
+			assert.NoError(t, os.WriteFile(filepath.Join(destination, "original.php"), []byte("<?php"), 0600))
+
+			if test.sourceExists {
+				assert.NoError(t, os.MkdirAll(source, 0750))
+				assert.NoError(t, os.WriteFile(filepath.Join(source, "archived.php"), []byte("<?php"), 0600))
+			}
+
+			err := replaceDirectory(source, destination)
+			assert.Equal(t, test.expectError, err != nil)
+
+			entries, err := os.ReadDir(destination)
+			assert.NoError(t, err)
+
+			files := []string{}
+			for _, entry := range entries {
+				files = append(files, entry.Name())
+			}
+
+			assert.Equal(t, test.expectedFiles, files)
+			assert.NoDirExists(t, destination+"-backup")
+		})
+	}
+}
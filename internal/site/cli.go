@@ -3,52 +3,171 @@ package site
 import (
 	"fmt"
 	"os/exec"
+	"path"
+	"strconv"
 	"strings"
 
 	"github.com/ChrisWiegman/kana/internal/console"
 	"github.com/ChrisWiegman/kana/internal/docker"
 )
 
+// wpCliResultMarker is written after each command run by WPCliBatch, followed by its index and exit code.
+const wpCliResultMarker = "KANA_WP_CLI_RESULT"
+
+// WPCliResult is the result of one of the commands run by WPCliBatch.
+type WPCliResult struct {
+	Command []string
+	Code    int64
+	Output  string
+}
+
 func Command(name string, arg ...string) *exec.Cmd {
 	return exec.Command(name, arg...)
 }
 
 // RunWPCli Runs a wp-cli command returning it's output and any errors.
 func (s *Site) WPCli(command []string, interactive bool, consoleOutput *console.Console) (statusCode int64, output string, err error) {
-	mounts := s.dockerClient.ContainerGetMounts(fmt.Sprintf("kana-%s-wordpress", s.settings.Get("name")))
+	fullCommand := []string{
+		"wp",
+		"--path=/var/www/html",
+	}
+
+	fullCommand = append(fullCommand, command...)
+
+	return s.runCliContainer(fullCommand, interactive, consoleOutput)
+}
+
+// WPCliBatch Runs several wp-cli commands one after another in a single container and returns the result of each.
+// A command that fails doesn't stop the ones after it.
+func (s *Site) WPCliBatch(commands [][]string, consoleOutput *console.Console) ([]WPCliResult, error) {
+	if len(commands) == 0 {
+		return []WPCliResult{}, nil
+	}
+
+	script := []string{}
+
+	for i, command := range commands {
+		fullCommand := append([]string{"wp", "--path=/var/www/html"}, command...)
+
+		// The marker goes on its own line so output without a trailing newline can't hide it
+		script = append(script, fmt.Sprintf(
+			"%s; code=$?; printf '\\n%s %d %%s\\n' \"$code\"",
+			shellQuote(fullCommand...),
+			wpCliResultMarker,
+			i))
+	}
+
+	_, output, err := s.runCliContainer([]string{"sh", "-c", strings.Join(script, "\n")}, false, consoleOutput)
+	if err != nil {
+		return []WPCliResult{}, err
+	}
+
+	return parseWPCliBatch(commands, output), nil
+}
+
+// parseWPCliBatch Splits the output of a batch on the markers written after each command.
+func parseWPCliBatch(commands [][]string, output string) []WPCliResult {
+	results := make([]WPCliResult, len(commands))
+
+	for i := range commands {
+		// Commands without a marker never finished
+		results[i] = WPCliResult{Command: commands[i], Code: 1}
+	}
+
+	var commandOutput []string
 
-	for _, mount := range mounts {
-		if strings.Contains(mount.Destination, "/var/www/html/wp-content/plugins/") {
-			err = s.settings.Set("type", "plugin")
-			if err != nil {
-				return 1, "", err
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[0] == wpCliResultMarker {
+			index, indexErr := strconv.Atoi(fields[1])
+			code, codeErr := strconv.ParseInt(fields[2], 10, 64)
+
+			if indexErr == nil && codeErr == nil && index >= 0 && index < len(results) {
+				results[index].Code = code
+				results[index].Output = strings.TrimSpace(strings.Join(commandOutput, "\n"))
 			}
+
+			commandOutput = []string{}
+
+			continue
 		}
 
-		if strings.Contains(mount.Destination, "/var/www/html/wp-content/themes/") {
-			err = s.settings.Set("type", "theme")
-			if err != nil {
-				return 1, "", err
-			}
+		commandOutput = append(commandOutput, line)
+	}
+
+	return results
+}
+
+// runCliContainer Runs the given command in a wp-cli container connected to the site.
+func (s *Site) runCliContainer(
+	command []string,
+	interactive bool,
+	consoleOutput *console.Console) (statusCode int64, output string, err error) {
+	mounts := s.dockerClient.ContainerGetMounts(fmt.Sprintf("kana-%s-wordpress", s.settings.Get("name")))
+
+	// Keep the type of a running site, which may differ from the current directory
+	if projectType := s.getProjectType(mounts); projectType != DefaultType {
+		err = s.settings.Set("type", projectType)
+		if err != nil {
+			return 1, "", err
 		}
 	}
 
-	wordPressDirectory, err := s.getWordPressDirectory()
+	container, err := s.getCliContainer(command)
 	if err != nil {
 		return 1, "", err
 	}
 
-	appVolumes, err := s.getWordPressMounts(wordPressDirectory)
+	err = s.dockerClient.EnsureImage(container.Image, s.settings.Get("appDirectory"), s.settings.GetInt("updateInterval"), consoleOutput)
 	if err != nil {
 		return 1, "", err
 	}
 
-	fullCommand := []string{
-		"wp",
-		"--path=/var/www/html",
+	code, output, err := s.dockerClient.ContainerRunAndClean(&container, interactive)
+	if err != nil {
+		return code, "", err
 	}
 
-	fullCommand = append(fullCommand, command...)
+	return code, output, nil
+}
+
+// runCli Runs an arbitrary CLI command against the site's WordPress container.
+func (s *Site) WordPress(command string, restart, root bool) (docker.ExecResult, error) {
+	container := fmt.Sprintf("kana-%s-wordpress", s.settings.Get("name"))
+
+	output, err := s.dockerClient.ContainerExec(container, root, []string{command})
+	if err != nil {
+		return docker.ExecResult{}, err
+	}
+
+	if restart {
+		_, err = s.dockerClient.ContainerRestart(container)
+		return output, err
+	}
+
+	return output, nil
+}
+
+// getCliImage Returns the wp-cli image matching the site's PHP version.
+func (s *Site) getCliImage() string {
+	return fmt.Sprintf("wordpress:cli-php%s", s.settings.Get("php"))
+}
+
+// getCliContainer Returns the ephemeral WP-CLI container, connected to the site, that runs the given command.
+func (s *Site) getCliContainer(command []string) (docker.ContainerConfig, error) {
+	wordPressDirectory, err := s.getWordPressDirectory()
+	if err != nil {
+		return docker.ContainerConfig{}, err
+	}
+
+	appVolumes, err := s.getWordPressMounts(wordPressDirectory)
+	if err != nil {
+		return docker.ContainerConfig{}, err
+	}
+
+	appVolumes = append(appVolumes, s.getPHPIniMounts()...)
 
 	envVars := []string{
 		"IS_KANA_ENVIRONMENT=true",
@@ -56,11 +175,13 @@ func (s *Site) WPCli(command []string, interactive bool, consoleOutput *console.
 
 	isUsingSQLite, err := s.isUsingSQLite()
 	if err != nil {
-		return 1, "", err
+		return docker.ContainerConfig{}, err
 	}
 
 	if isUsingSQLite {
-		envVars = append(envVars, "KANA_SQLITE=true")
+		envVars = append(envVars,
+			"KANA_SQLITE=true",
+			fmt.Sprintf("WP_CLI_PACKAGES_DIR=%s", path.Join("/Site", wpCliPackagesDir)))
 	} else {
 		envVars = append(envVars,
 			fmt.Sprintf("WORDPRESS_DB_HOST=kana-%s-database", s.settings.Get("name")),
@@ -72,10 +193,10 @@ func (s *Site) WPCli(command []string, interactive bool, consoleOutput *console.
 
 	container := docker.ContainerConfig{
 		Name:        fmt.Sprintf("kana-%s-wordpress_cli", s.settings.Get("name")),
-		Image:       fmt.Sprintf("wordpress:cli-php%s", s.settings.Get("php")),
+		Image:       s.getCliImage(),
 		NetworkName: "kana",
 		HostName:    fmt.Sprintf("kana-%s-wordpress_cli", s.settings.Get("name")),
-		Command:     fullCommand,
+		Command:     command,
 		Env:         envVars,
 		Labels: map[string]string{
 			"kana.site": s.settings.Get("name"),
@@ -87,32 +208,9 @@ func (s *Site) WPCli(command []string, interactive bool, consoleOutput *console.
 		container.Env = append(container.Env, "KANA_ADMIN_LOGIN=true")
 	}
 
-	err = s.dockerClient.EnsureImage(container.Image, s.settings.Get("appDirectory"), s.settings.GetInt("updateInterval"), consoleOutput)
-	if err != nil {
-		return 1, "", err
-	}
-
-	code, output, err := s.dockerClient.ContainerRunAndClean(&container, interactive)
-	if err != nil {
-		return code, "", err
-	}
-
-	return code, output, nil
-}
-
-// runCli Runs an arbitrary CLI command against the site's WordPress container.
-func (s *Site) WordPress(command string, restart, root bool) (docker.ExecResult, error) {
-	container := fmt.Sprintf("kana-%s-wordpress", s.settings.Get("name"))
-
-	output, err := s.dockerClient.ContainerExec(container, root, []string{command})
-	if err != nil {
-		return docker.ExecResult{}, err
+	if s.settings.GetBool("redis") {
+		container.Env = append(container.Env, fmt.Sprintf("WORDPRESS_CONFIG_EXTRA=%s", s.getRedisConfig()))
 	}
 
-	if restart {
-		_, err = s.dockerClient.ContainerRestart(container)
-		return output, err
-	}
-
-	return output, nil
+	return container, nil
 }
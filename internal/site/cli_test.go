@@ -0,0 +1,75 @@
+package site
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWPCliBatch(t *testing.T) {
+	commands := [][]string{
+		{"plugin", "list"},
+		{"theme", "list"},
+	}
+
+	tests := []struct {
+		name     string
+		output   string
+		expected []WPCliResult
+	}{
+		{
+			"All markers",
+			"akismet\nhello\n\nKANA_WP_CLI_RESULT 0 0\ntwentytwentyfour\n\nKANA_WP_CLI_RESULT 1 0\n",
+			[]WPCliResult{
+				{Command: commands[0], Code: 0, Output: "akismet\nhello"},
+				{Command: commands[1], Code: 0, Output: "twentytwentyfour"},
+			},
+		},
+		{
+			"Failed command",
+			"Error: This does not seem to be a WordPress installation.\nKANA_WP_CLI_RESULT 0 1\ntwentytwentyfour\nKANA_WP_CLI_RESULT 1 0\n",
+			[]WPCliResult{
+				{Command: commands[0], Code: 1, Output: "Error: This does not seem to be a WordPress installation."},
+				{Command: commands[1], Code: 0, Output: "twentytwentyfour"},
+			},
+		},
+		{
+			"Missing marker",
+			"akismet\nKANA_WP_CLI_RESULT 0 0\ntwentytwentyfour\n",
+			[]WPCliResult{
+				{Command: commands[0], Code: 0, Output: "akismet"},
+				{Command: commands[1], Code: 1, Output: ""},
+			},
+		},
+		{
+			"No markers",
+			"",
+			[]WPCliResult{
+				{Command: commands[0], Code: 1, Output: ""},
+				{Command: commands[1], Code: 1, Output: ""},
+			},
+		},
+		{
+			"Invalid markers",
+			"akismet\nKANA_WP_CLI_RESULT 5 0\nKANA_WP_CLI_RESULT one 0\nKANA_WP_CLI_RESULT 1 0\n",
+			[]WPCliResult{
+				{Command: commands[0], Code: 1, Output: ""},
+				{Command: commands[1], Code: 0, Output: ""},
+			},
+		},
+		{
+			"CRLF line endings",
+			"akismet\r\nhello\r\n\r\nKANA_WP_CLI_RESULT 0 0\r\ntwentytwentyfour\r\nKANA_WP_CLI_RESULT 1 2\r\n",
+			[]WPCliResult{
+				{Command: commands[0], Code: 0, Output: "akismet\nhello"},
+				{Command: commands[1], Code: 2, Output: "twentytwentyfour"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, parseWPCliBatch(commands, test.output))
+		})
+	}
+}
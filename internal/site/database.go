@@ -3,10 +3,10 @@ package site
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/ChrisWiegman/kana/internal/console"
 	"github.com/ChrisWiegman/kana/internal/docker"
@@ -22,10 +22,6 @@ func (s *Site) ExportDatabase(args []string, consoleOutput *console.Console) (st
 		return "", err
 	}
 
-	if isUsingSQLite {
-		return "", fmt.Errorf("SQLite databases cannot be exported")
-	}
-
 	cwd, err := os.Getwd()
 	if err != nil {
 		return "", err
@@ -38,22 +34,14 @@ func (s *Site) ExportDatabase(args []string, consoleOutput *console.Console) (st
 		exportFile = filepath.Join(cwd, args[0])
 	}
 
-	exportCommand := []string{
-		"db",
-		"export",
-		"--add-drop-table",
-		"/Site/export.sql",
-	}
-
-	code, output, err := s.WPCli(exportCommand, false, consoleOutput)
-	if err != nil || code != 0 {
-		errorMessage := ""
-
-		if err != nil {
-			errorMessage = err.Error()
+	err = s.exportDatabaseToSite("export.sql", consoleOutput)
+	if err != nil {
+		if !isUsingSQLite {
+			return "", err
 		}
 
-		return "", fmt.Errorf("database export failed: %s\n%s", errorMessage, output)
+		// Fall back to a copy of the raw database file if SQLite can't be exported as SQL
+		return s.copySQLiteDatabase(exportFile, err, consoleOutput)
 	}
 
 	err = copyFile(filepath.Join(s.settings.Get("siteDirectory"), "export.sql"), exportFile)
@@ -65,15 +53,6 @@ func (s *Site) ExportDatabase(args []string, consoleOutput *console.Console) (st
 }
 
 func (s *Site) ImportDatabase(file string, preserve bool, replaceDomain string, consoleOutput *console.Console) error {
-	isUsingSQLite, err := s.isUsingSQLite()
-	if err != nil {
-		return err
-	}
-
-	if isUsingSQLite {
-		return fmt.Errorf("SQLite databases cannot be imported")
-	}
-
 	cwd, err := os.Getwd()
 	if err != nil {
 		return err
@@ -91,6 +70,51 @@ func (s *Site) ImportDatabase(file string, preserve bool, replaceDomain string,
 		return err
 	}
 
+	return s.importDatabaseFromSite("import.sql", preserve, replaceDomain, consoleOutput)
+}
+
+// exportDatabaseToSite Exports the site's database to the given file in the site directory.
+func (s *Site) exportDatabaseToSite(fileName string, consoleOutput *console.Console) error {
+	isUsingSQLite, err := s.isUsingSQLite()
+	if err != nil {
+		return err
+	}
+
+	if isUsingSQLite {
+		return s.exportSQLiteDatabase(fileName, consoleOutput)
+	}
+
+	exportCommand := []string{
+		"db",
+		"export",
+		"--add-drop-table",
+		path.Join("/Site", fileName),
+	}
+
+	code, output, err := s.WPCli(exportCommand, false, consoleOutput)
+	if err != nil || code != 0 {
+		return wpCliError("database export failed", err, output)
+	}
+
+	return nil
+}
+
+// importDatabaseFromSite Imports the given file from the site directory into the site's database.
+func (s *Site) importDatabaseFromSite(fileName string, preserve bool, replaceDomain string, consoleOutput *console.Console) error {
+	isUsingSQLite, err := s.isUsingSQLite()
+	if err != nil {
+		return err
+	}
+
+	if isUsingSQLite {
+		err = s.importSQLiteDatabase(fileName, preserve, consoleOutput)
+		if err != nil {
+			return err
+		}
+
+		return s.replaceDomain(replaceDomain, consoleOutput)
+	}
+
 	if !preserve {
 		consoleOutput.Println("Dropping the existing database.")
 
@@ -105,17 +129,14 @@ func (s *Site) ImportDatabase(file string, preserve bool, replaceDomain string,
 			"create",
 		}
 
-		var code int64
-		var output string
-
-		code, output, err = s.WPCli(dropCommand, false, consoleOutput)
+		code, output, err := s.WPCli(dropCommand, false, consoleOutput)
 		if err != nil || code != 0 {
-			return fmt.Errorf("drop database failed: %s\n%s", err.Error(), output)
+			return wpCliError("drop database failed", err, output)
 		}
 
 		code, output, err = s.WPCli(createCommand, false, consoleOutput)
 		if err != nil || code != 0 {
-			return fmt.Errorf("create database failed: %s\n%s", err.Error(), output)
+			return wpCliError("create database failed", err, output)
 		}
 	}
 
@@ -124,28 +145,35 @@ func (s *Site) ImportDatabase(file string, preserve bool, replaceDomain string,
 	importCommand := []string{
 		"db",
 		"import",
-		"/Site/import.sql",
+		path.Join("/Site", fileName),
 	}
 
 	code, output, err := s.WPCli(importCommand, false, consoleOutput)
 	if err != nil || code != 0 {
-		return fmt.Errorf("database import failed: %s\n%s", err.Error(), output)
+		return wpCliError("database import failed", err, output)
 	}
 
-	if replaceDomain != "" {
-		consoleOutput.Println("Replacing the old domain name")
+	return s.replaceDomain(replaceDomain, consoleOutput)
+}
 
-		replaceCommand := []string{
-			"search-replace",
-			replaceDomain,
-			s.settings.GetDomain(),
-			"--all-tables",
-		}
+// replaceDomain Replaces an old domain throughout the database with the site's domain.
+func (s *Site) replaceDomain(oldDomain string, consoleOutput *console.Console) error {
+	if oldDomain == "" || oldDomain == s.settings.GetDomain() {
+		return nil
+	}
 
-		code, output, err := s.WPCli(replaceCommand, false, consoleOutput)
-		if err != nil || code != 0 {
-			return fmt.Errorf("replace domain failed failed: %s\n%s", err.Error(), output)
-		}
+	consoleOutput.Println("Replacing the old domain name")
+
+	replaceCommand := []string{
+		"search-replace",
+		oldDomain,
+		s.settings.GetDomain(),
+		"--all-tables",
+	}
+
+	code, output, err := s.WPCli(replaceCommand, false, consoleOutput)
+	if err != nil || code != 0 {
+		return wpCliError("replace domain failed", err, output)
 	}
 
 	return nil
@@ -186,6 +214,9 @@ func (s *Site) getDatabaseContainer(databaseDir string, appContainers []docker.C
 			{Port: "3306", Protocol: "tcp"},
 		},
 		Env: envVars,
+		// Connecting over TCP as the site's user only works once the server has finished initializing
+		HealthCheck: s.getHealthCheck(
+			"$(command -v mariadb-admin || command -v mysqladmin) ping -h 127.0.0.1 -u wordpress -pwordpress --silent"),
 		Labels: map[string]string{
 			"kana.type": "database",
 			"kana.site": s.settings.Get("name"),
@@ -230,7 +261,8 @@ func (s *Site) getDatabasePort() string {
 	var databasePort container.Port
 
 	for i := range containers {
-		if containers[i].Image == fmt.Sprintf("%s:%s", s.settings.Get("database"), s.settings.Get("databaseVersion")) {
+		if containers[i].Image == fmt.Sprintf("%s:%s", s.settings.Get("database"), s.settings.Get("databaseVersion")) &&
+			len(containers[i].Ports) > 0 {
 			databasePort = containers[i].Ports[0]
 		}
 	}
@@ -274,6 +306,11 @@ func (s *Site) maybeSetupSQLite() error {
 }
 
 func (s *Site) isUsingSQLite() (bool, error) {
+	// Until the site is running the setting is the only way to know which database will be used
+	if !s.IsSiteRunning() {
+		return s.settings.Get("database") == "sqlite", nil
+	}
+
 	output, err := s.WordPress("echo $KANA_SQLITE", false, false)
 	if err != nil {
 		return false, err
@@ -285,30 +322,3 @@ func (s *Site) isUsingSQLite() (bool, error) {
 
 	return false, nil
 }
-
-// verifySite verifies if a site is up and running without error.
-func (s *Site) verifyDatabase(consoleOutput *console.Console) error {
-	checkCommand := []string{
-		"db",
-		"check",
-	}
-
-	databaseOK := false
-	checkAttempt := 0
-
-	for !databaseOK {
-		code, _, err := s.WPCli(checkCommand, false, consoleOutput)
-		if err != nil || code != 0 {
-			checkAttempt++ // Increment the check attempt counter
-			time.Sleep(time.Second)
-		} else {
-			return nil
-		}
-
-		if checkAttempt == s.maxVerificationRetries {
-			return fmt.Errorf("database verification failed")
-		}
-	}
-
-	return nil
-}
@@ -0,0 +1,91 @@
+package site
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/docker"
+	"github.com/ChrisWiegman/kana/internal/settings"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+const (
+	dnsContainerName = "kana-dns"
+	corednsVersion   = "1.11.3"
+)
+
+// IsDNSRunning Returns true if Kana's DNS server is running.
+func (s *Site) IsDNSRunning() bool {
+	return s.dockerClient.IsContainerRunning(dnsContainerName)
+}
+
+// StartDNS Starts Kana's DNS server without waiting for a site to start.
+func (s *Site) StartDNS(consoleOutput *console.Console) error {
+	_, _, err := s.dockerClient.EnsureNetwork("kana")
+	if err != nil {
+		return err
+	}
+
+	dnsContainer := s.getDNSContainer()
+
+	err = s.dockerClient.EnsureImage(
+		dnsContainer.Image,
+		s.settings.Get("appDirectory"),
+		s.settings.GetInt("updateInterval"),
+		consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.dockerClient.ContainerRun(&dnsContainer, false, false)
+
+	return err
+}
+
+// StopDNS Stops Kana's DNS server.
+func (s *Site) StopDNS() error {
+	_, err := s.dockerClient.ContainerStop(dnsContainerName)
+
+	return err
+}
+
+// getDNSContainer Returns the configuration of the CoreDNS container answering for Kana's domains.
+func (s *Site) getDNSContainer() docker.ContainerConfig {
+	dnsPort := fmt.Sprint(s.settings.GetInt("dnsPort"))
+
+	// Only listen on the local machine as every answer points back to it anyway
+	dnsPorts := []docker.ExposedPorts{
+		{Port: dnsPort, Protocol: "udp", HostIP: "127.0.0.1"},
+		{Port: dnsPort, Protocol: "tcp", HostIP: "127.0.0.1"},
+	}
+
+	return docker.ContainerConfig{
+		Name:        dnsContainerName,
+		Image:       "coredns/coredns:" + corednsVersion,
+		Ports:       dnsPorts,
+		NetworkName: "kana",
+		HostName:    "kanadns",
+		Command:     []string{"-conf", "/etc/coredns/Corefile", "-dns.port", dnsPort},
+		Labels: map[string]string{
+			"kana.global": "true",
+		},
+		Volumes: []mount.Mount{
+			{
+				Type:   mount.TypeBind,
+				Source: filepath.Join(s.settings.Get("appDirectory"), "config", "dns", "Corefile"),
+				Target: "/etc/coredns/Corefile",
+			},
+		},
+	}
+}
+
+// maybeUpdateDNSResolver Adds new custom domains to the systemd-resolved drop-in if Kana's DNS server is in use.
+func (s *Site) maybeUpdateDNSResolver(consoleOutput *console.Console) error {
+	if !s.settings.GetBool("dns") || !settings.IsDNSResolverInstalled() {
+		return nil
+	}
+
+	return settings.InstallDNSResolver(s.settings.Get("appDirectory"), int(s.settings.GetInt("dnsPort")), consoleOutput)
+}
@@ -0,0 +1,50 @@
+package site
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/settings"
+)
+
+// getHostRule Returns the Traefik rule matching the site's domain and any extra domains from the domains setting.
+func (s *Site) getHostRule() string {
+	hostRules := []string{}
+
+	for _, siteDomain := range s.settings.GetDomains() {
+		hostRules = append(hostRules, fmt.Sprintf("Host(`%s`)", siteDomain))
+	}
+
+	// Each site of a subdomain multisite lives one level below the main site
+	if s.settings.Get("multisite") == "subdomain" {
+		hostRules = append(hostRules, fmt.Sprintf("HostRegexp(`^[a-z0-9-]+\\.%s$`)", regexp.QuoteMeta(s.settings.GetDomain())))
+	}
+
+	return strings.Join(hostRules, " || ")
+}
+
+// maybeUpdateHostsFile Points the site's extra domains at the local machine if Kana is managing /etc/hosts.
+func (s *Site) maybeUpdateHostsFile(consoleOutput *console.Console) error {
+	if !s.settings.GetBool("manageHosts") {
+		return nil
+	}
+
+	// The site's own domain already resolves to the local machine
+	return settings.UpdateHostsFile(s.settings.Get("name"), s.settings.GetDomains()[1:], consoleOutput)
+}
+
+// RemoveDomains Removes the site's certificate and, if Kana is managing /etc/hosts, its entries there.
+func (s *Site) RemoveDomains(consoleOutput *console.Console) error {
+	_, err := settings.EnsureSiteCert(s.settings.Get("appDirectory"), s.settings.Get("name"), []string{})
+	if err != nil {
+		return err
+	}
+
+	if !s.settings.GetBool("manageHosts") {
+		return nil
+	}
+
+	return settings.UpdateHostsFile(s.settings.Get("name"), []string{}, consoleOutput)
+}
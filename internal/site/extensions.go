@@ -0,0 +1,339 @@
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/docker"
+	"github.com/ChrisWiegman/kana/internal/settings"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/mitchellh/go-homedir"
+)
+
+const gitImage = "alpine/git:latest"
+
+// getExtensions Returns the parsed entries of the plugins setting or, for "theme", the theme setting.
+func (s *Site) getExtensions(kind string) ([]settings.Extension, error) {
+	entries := s.settings.GetSlice("plugins")
+
+	if kind == "theme" {
+		// A theme project is the site's theme so there is nothing else to install
+		if s.settings.Get("type") == "theme" {
+			return []settings.Extension{}, nil
+		}
+
+		entries = []string{s.settings.Get("theme")}
+	}
+
+	extensions := []settings.Extension{}
+
+	for _, entry := range entries {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		extension, err := settings.ParseExtension(entry)
+		if err != nil {
+			return extensions, err
+		}
+
+		extensions = append(extensions, extension)
+	}
+
+	return extensions, nil
+}
+
+// resolveLocalPath Returns the absolute path of a local directory, relative paths being relative to the project.
+func (s *Site) resolveLocalPath(localPath string) (string, error) {
+	localPath, err := homedir.Expand(localPath)
+	if err != nil {
+		return "", err
+	}
+
+	if !filepath.IsAbs(localPath) {
+		localPath = filepath.Join(s.settings.Get("workingDirectory"), localPath)
+	}
+
+	info, err := os.Stat(localPath)
+	if err != nil || !info.IsDir() {
+		return "", fmt.Errorf("the local path, %s, must be a directory", localPath)
+	}
+
+	return filepath.Clean(localPath), nil
+}
+
+// getExtensionMounts Maps the local plugins and theme into wp-content so changes to them show up immediately.
+func (s *Site) getExtensionMounts(appDir string) ([]mount.Mount, error) {
+	extensionMounts := []mount.Mount{}
+
+	for _, kind := range []string{"plugin", "theme"} {
+		extensions, err := s.getExtensions(kind)
+		if err != nil {
+			return extensionMounts, err
+		}
+
+		for _, extension := range extensions {
+			if extension.Source != settings.ExtensionSourcePath {
+				continue
+			}
+
+			source, err := s.resolveLocalPath(extension.Path)
+			if err != nil {
+				return extensionMounts, fmt.Errorf("unable to mount the local plugin or theme, %s: %s", extension.Entry, err)
+			}
+
+			err = os.MkdirAll(
+				filepath.Join(appDir, "wp-content", kind+"s", extension.Slug),
+				os.FileMode(defaultDirPermissions))
+			if err != nil {
+				return extensionMounts, err
+			}
+
+			extensionMounts = append(extensionMounts, mount.Mount{
+				Type:   mount.TypeBind,
+				Source: source,
+				Target: filepath.Join("/var/www/html/wp-content", kind+"s", extension.Slug),
+			})
+		}
+	}
+
+	return extensionMounts, nil
+}
+
+// getProjectType Returns the type of the project from the mounts of its WordPress container.
+func (s *Site) getProjectType(mounts []container.MountPoint) string {
+	for _, mount := range mounts {
+		// Only the project itself counts. Local plugins and themes are mounted alongside it.
+		switch mount.Destination {
+		case filepath.Join("/var/www/html/wp-content/plugins", s.settings.Get("name")):
+			return "plugin"
+		case filepath.Join("/var/www/html/wp-content/themes", s.settings.Get("name")):
+			return "theme"
+		}
+	}
+
+	return DefaultType
+}
+
+// getInstalledExtensions Returns the plugins and themes installed on the site.
+func (s *Site) getInstalledExtensions(consoleOutput *console.Console) (plugins, themes []PluginInfo, err error) {
+	results, err := s.WPCliBatch([][]string{
+		{"plugin", "list", "--format=json", "--fields=name,status,version"},
+		{"theme", "list", "--format=json", "--fields=name,status,version"},
+	}, consoleOutput)
+	if err != nil {
+		return plugins, themes, err
+	}
+
+	for i, list := range []*[]PluginInfo{&plugins, &themes} {
+		if results[i].Code != 0 {
+			return plugins, themes, fmt.Errorf("unable to list the installed plugins and themes: %s", results[i].Output)
+		}
+
+		err = json.Unmarshal([]byte(results[i].Output), list)
+		if err != nil {
+			return plugins, themes, err
+		}
+	}
+
+	return plugins, themes, nil
+}
+
+// getExtensionCommand Returns the wp-cli command that installs and activates a plugin or theme, or nil if it is already
+// installed at the requested version.
+func getExtensionCommand(kind string, extension settings.Extension, installed []PluginInfo) []string {
+	var installedVersion string
+
+	isInstalled := false
+
+	for _, plugin := range installed {
+		if plugin.Name == extension.Slug {
+			installedVersion = plugin.Version
+			isInstalled = true
+		}
+	}
+
+	activateCommand := []string{kind, "activate", extension.Slug}
+
+	// Themes are activated even when installed as the site can only have one
+	if kind == "plugin" {
+		activateCommand = nil
+	}
+
+	switch extension.Source {
+	case settings.ExtensionSourcePath, settings.ExtensionSourceGit:
+		// The files are already in place so they only need activating
+		return []string{kind, "activate", extension.Slug}
+	case settings.ExtensionSourceZip:
+		// Zips without a version in their name can't be compared so are only installed when missing
+		if isInstalled && (extension.Version == "" || extension.Version == installedVersion) {
+			return activateCommand
+		}
+
+		command := []string{kind, "install", extension.URL, "--activate"}
+
+		// Replace the installed copy with the version in the zip's name
+		if isInstalled {
+			command = append(command, "--force")
+		}
+
+		return command
+	}
+
+	if isInstalled && (extension.Version == "" || extension.Version == installedVersion) {
+		return activateCommand
+	}
+
+	command := []string{kind, "install", extension.Slug, "--activate"}
+
+	if extension.Version != "" {
+		command = append(command, fmt.Sprintf("--version=%s", extension.Version))
+	}
+
+	// Replace the installed copy with the pinned version
+	if isInstalled {
+		command = append(command, "--force")
+	}
+
+	return command
+}
+
+// syncGitExtension Clones the git repository of a plugin or theme into wp-content, or updates it to the configured ref.
+func (s *Site) syncGitExtension(kind string, extension settings.Extension, consoleOutput *console.Console) error {
+	wordPressDirectory, err := s.getWordPressDirectory()
+	if err != nil {
+		return err
+	}
+
+	extensionDirectory := filepath.Join(wordPressDirectory, "wp-content", kind+"s", extension.Slug)
+
+	err = os.MkdirAll(extensionDirectory, os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return err
+	}
+
+	ref := extension.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	// Only fetch the requested ref so installs are quick and always match the configuration
+	script := strings.Join([]string{
+		"set -e",
+		"if [ ! -d .git ]; then",
+		"  if [ -n \"$(ls -A)\" ]; then echo 'The directory exists and is not a git repository.'; exit 1; fi",
+		"  git init -q",
+		"  git remote add origin " + shellQuote(extension.URL),
+		"fi",
+		"git remote set-url origin " + shellQuote(extension.URL),
+		"git fetch -q --depth 1 origin " + shellQuote(ref),
+		"git checkout -q --force FETCH_HEAD",
+	}, "\n")
+
+	gitContainer := docker.ContainerConfig{
+		Name:       fmt.Sprintf("kana-%s-git", s.settings.Get("name")),
+		Image:      gitImage,
+		Entrypoint: []string{"sh", "-c"},
+		Command:    []string{script},
+		// The local user has no home directory in the image so give git somewhere to write its config
+		Env:        []string{"HOME=/tmp"},
+		WorkingDir: "/extension",
+		Labels: map[string]string{
+			"kana.site": s.settings.Get("name"),
+		},
+		Volumes: []mount.Mount{
+			{
+				Type:   mount.TypeBind,
+				Source: extensionDirectory,
+				Target: "/extension",
+			},
+		},
+	}
+
+	err = s.dockerClient.EnsureImage(
+		gitContainer.Image,
+		s.settings.Get("appDirectory"),
+		s.settings.GetInt("updateInterval"),
+		consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	// ContainerRunAndClean runs as the local user so the cloned files belong to them rather than root
+	code, output, err := s.dockerClient.ContainerRunAndClean(&gitContainer, false)
+	if err != nil {
+		return err
+	}
+
+	if code != 0 {
+		return fmt.Errorf("%s", strings.TrimSpace(output))
+	}
+
+	return nil
+}
+
+// getExportedPlugins Returns the plugins to write to the exported configuration, pinned to their installed versions.
+func (s *Site) getExportedPlugins(installed []PluginInfo) (plugins []string, hasDefaultPlugins bool) {
+	configured := map[string]settings.Extension{}
+
+	extensions, _ := s.getExtensions("plugin")
+
+	for _, extension := range extensions {
+		configured[extension.Slug] = extension
+	}
+
+	plugins = []string{}
+
+	for _, plugin := range installed {
+		if plugin.Name == "hello" ||
+			plugin.Name == "akismet" {
+			hasDefaultPlugins = true
+		}
+
+		if plugin.Status == "dropin" ||
+			plugin.Status == "must-use" ||
+			plugin.Name == s.settings.Get("name") ||
+			plugin.Name == "hello" ||
+			plugin.Name == "akismet" {
+			continue
+		}
+
+		// Plugins from elsewhere are exported as they were configured
+		if extension, ok := configured[plugin.Name]; ok && extension.Source != settings.ExtensionSourceWordPress {
+			plugins = append(plugins, extension.String())
+			continue
+		}
+
+		extension := settings.Extension{Source: settings.ExtensionSourceWordPress, Slug: plugin.Name, Version: plugin.Version}
+
+		plugins = append(plugins, extension.String())
+	}
+
+	return plugins, hasDefaultPlugins
+}
+
+// getExportedTheme Returns the theme to write to the exported configuration, pinned to its installed version.
+func (s *Site) getExportedTheme(installed []PluginInfo) string {
+	extensions, _ := s.getExtensions("theme")
+	if len(extensions) == 0 {
+		return ""
+	}
+
+	extension := extensions[0]
+
+	if extension.Source == settings.ExtensionSourceWordPress {
+		for _, theme := range installed {
+			if theme.Name == extension.Slug {
+				extension.Version = theme.Version
+			}
+		}
+	}
+
+	return extension.String()
+}
@@ -0,0 +1,41 @@
+package site
+
+import (
+	"testing"
+
+	"github.com/ChrisWiegman/kana/internal/settings"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetExtensionCommandZip(t *testing.T) {
+	installed := []PluginInfo{{Name: "my-plugin", Status: "active", Version: "1.0"}}
+
+	tests := []struct {
+		name     string
+		entry    string
+		expected []string
+	}{
+		{
+			"Missing",
+			"https://example.com/other-plugin-1.0.zip",
+			[]string{"plugin", "install", "https://example.com/other-plugin-1.0.zip", "--activate"}},
+		{"Same version", "https://example.com/my-plugin-1.0.zip", nil},
+		{
+			"New version",
+			"https://example.com/my-plugin-1.1.zip",
+			[]string{"plugin", "install", "https://example.com/my-plugin-1.1.zip", "--activate", "--force"}},
+		{"No version", "https://example.com/my-plugin.zip", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			extension, err := settings.ParseExtension(test.entry)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, test.expected, getExtensionCommand("plugin", extension, installed))
+		})
+	}
+}
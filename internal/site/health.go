@@ -0,0 +1,30 @@
+package site
+
+import (
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+const (
+	healthCheckInterval = 2 * time.Second
+	healthCheckTimeout  = 5 * time.Second
+	healthCheckRetries  = 3
+)
+
+// getHealthCheck Returns a health check that runs the given shell command in the container.
+// Failures during the start period don't count so slow first starts aren't marked unhealthy.
+func (s *Site) getHealthCheck(command string) *container.HealthConfig {
+	return &container.HealthConfig{
+		Test:        []string{"CMD-SHELL", command},
+		Interval:    healthCheckInterval,
+		Timeout:     healthCheckTimeout,
+		StartPeriod: s.getHealthCheckTimeout(),
+		Retries:     healthCheckRetries,
+	}
+}
+
+// getHealthCheckTimeout Returns how long to wait for the site's services to become healthy.
+func (s *Site) getHealthCheckTimeout() time.Duration {
+	return time.Duration(s.settings.GetInt("healthCheckTimeout")) * time.Second
+}
@@ -1,54 +1,14 @@
 package site
 
 import (
-	"context"
-	"crypto/tls"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 	"strings"
 
 	"github.com/ChrisWiegman/kana/internal/docker"
 )
 
-// checkStatusCode returns true on 200 or false.
-func checkStatusCode(checkURL string) (bool, error) {
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, checkURL, http.NoBody)
-	if err != nil {
-		return false, err
-	}
-
-	// Ignore SSL check as we're using our self-signed cert for development
-	clientTransport := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
-	}
-
-	client := &http.Client{
-		Transport: clientTransport,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		},
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return false, err
-	}
-
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			panic(err)
-		}
-	}()
-
-	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusFound {
-		return true, nil
-	}
-
-	return false, nil
-}
-
 // copyFile Copies a file on the user's host from one place to another.
 func copyFile(src, dest string) error {
 	srcStat, err := os.Stat(src)
@@ -86,6 +46,17 @@ func copyFile(src, dest string) error {
 	return err
 }
 
+// wpCliError Builds a consistent error from a failed wp-cli command and its output.
+func wpCliError(message string, err error, output string) error {
+	errorMessage := ""
+
+	if err != nil {
+		errorMessage = err.Error()
+	}
+
+	return fmt.Errorf("%s: %s\n%s", message, errorMessage, output)
+}
+
 // handleImageError Handles errors related to image detection and provides more helpful error messages.
 func (s *Site) handleImageError(container *docker.ContainerConfig, err error) error {
 	if strings.Contains(err.Error(), "manifest unknown") {
@@ -109,3 +80,14 @@ func (s *Site) handleImageError(container *docker.ContainerConfig, err error) er
 
 	return err
 }
+
+// shellQuote Quotes each argument so a shell passes it to the command exactly as given.
+func shellQuote(args ...string) string {
+	quotedArgs := make([]string, len(args))
+
+	for i, arg := range args {
+		quotedArgs[i] = "'" + strings.ReplaceAll(arg, "'", `'"'"'`) + "'"
+	}
+
+	return strings.Join(quotedArgs, " ")
+}
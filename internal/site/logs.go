@@ -0,0 +1,216 @@
+package site
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/docker"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// LogMessage represents a single line of log output from one of the site's services.
+type LogMessage struct {
+	Service, Message string
+}
+
+type logContainer struct {
+	service, name string
+}
+
+// logWriter splits a log stream into lines, filters them and prints each with the prefix of its service.
+type logWriter struct {
+	service       string
+	prefix        string
+	filter        *regexp.Regexp
+	lock          *sync.Mutex
+	consoleOutput *console.Console
+	buffer        []byte
+}
+
+var logServices = []string{
+	"wordpress",
+	"database",
+	"mailpit",
+	"phpmyadmin",
+	"redis",
+	"traefik",
+}
+
+// GetLogServices returns the services whose logs can be viewed with the logs command.
+func GetLogServices() []string {
+	return logServices
+}
+
+// StreamLogs Streams the logs of the requested services, or of all the site's running services if none are given, to the console.
+func (s *Site) StreamLogs(services []string, options docker.LogOptions, grep string, consoleOutput *console.Console) error {
+	var filter *regexp.Regexp
+	var err error
+
+	if grep != "" {
+		filter, err = regexp.Compile(grep)
+		if err != nil {
+			return fmt.Errorf("the grep pattern, %s, is not a valid regular expression: %s", grep, err)
+		}
+	}
+
+	containers, err := s.getLogContainers(services)
+	if err != nil {
+		return err
+	}
+
+	prefixWidth := 0
+
+	for _, logContainer := range containers {
+		prefixWidth = max(prefixWidth, len(logContainer.service))
+	}
+
+	var outputLock sync.Mutex
+
+	errs := new(errgroup.Group)
+
+	for _, logContainer := range containers {
+		writer := &logWriter{
+			service:       logContainer.service,
+			prefix:        getLogPrefix(logContainer.service, prefixWidth, consoleOutput),
+			filter:        filter,
+			lock:          &outputLock,
+			consoleOutput: consoleOutput,
+		}
+
+		errs.Go(func() error {
+			err := s.dockerClient.ContainerLogs(logContainer.name, options, writer)
+			writer.Flush()
+
+			return err
+		})
+	}
+
+	return errs.Wait()
+}
+
+// getLogContainers Returns the containers matching the requested services in the order the services were given.
+func (s *Site) getLogContainers(services []string) ([]logContainer, error) {
+	containers := []logContainer{}
+
+	siteContainers, err := s.dockerClient.ContainerList(s.settings.Get("name"))
+	if err != nil {
+		return containers, err
+	}
+
+	runningServices := map[string]string{}
+
+	for i := range siteContainers {
+		if siteContainers[i].State != "running" || len(siteContainers[i].Names) == 0 {
+			continue
+		}
+
+		service, ok := siteContainers[i].Labels["kana.type"]
+		if ok {
+			runningServices[service] = strings.Trim(siteContainers[i].Names[0], "/")
+		}
+	}
+
+	if len(services) == 0 {
+		for _, service := range logServices {
+			if containerName, ok := runningServices[service]; ok {
+				containers = append(containers, logContainer{service: service, name: containerName})
+			}
+		}
+
+		if len(containers) == 0 {
+			return containers, fmt.Errorf("the site is not running. Please run 'kana start' to start the site")
+		}
+
+		return containers, nil
+	}
+
+	for _, service := range services {
+		if service == "traefik" {
+			containers = append(containers, logContainer{service: service, name: traefikContainerName})
+			continue
+		}
+
+		containerName, ok := runningServices[service]
+		if !ok {
+			return containers, fmt.Errorf("the %s service is not running for this site", service)
+		}
+
+		containers = append(containers, logContainer{service: service, name: containerName})
+	}
+
+	return containers, nil
+}
+
+// getLogPrefix Returns the padded and colored prefix used to identify each service in the log output.
+func getLogPrefix(service string, width int, consoleOutput *console.Console) string {
+	prefix := fmt.Sprintf("%-*s |", width, service)
+
+	switch service {
+	case "wordpress":
+		return consoleOutput.Blue(prefix)
+	case "database":
+		return consoleOutput.Green(prefix)
+	case "mailpit":
+		return consoleOutput.Yellow(prefix)
+	case "phpmyadmin":
+		return consoleOutput.Magenta(prefix)
+	case "redis":
+		return consoleOutput.Red(prefix)
+	default:
+		return consoleOutput.Cyan(prefix)
+	}
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.buffer = append(w.buffer, p...)
+
+	for {
+		i := bytes.IndexByte(w.buffer, '\n')
+		if i < 0 {
+			break
+		}
+
+		w.printLine(string(w.buffer[:i]))
+		w.buffer = w.buffer[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush prints anything left in the buffer that didn't end with a newline.
+func (w *logWriter) Flush() {
+	if len(w.buffer) > 0 {
+		w.printLine(string(w.buffer))
+		w.buffer = nil
+	}
+}
+
+func (w *logWriter) printLine(line string) {
+	line = strings.TrimRight(line, "\r")
+
+	if w.filter != nil && !w.filter.MatchString(line) {
+		return
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.consoleOutput.JSON {
+		str, _ := json.Marshal(LogMessage{
+			Service: w.service,
+			Message: line,
+		})
+
+		fmt.Println(string(str))
+
+		return
+	}
+
+	fmt.Printf("%s %s\n", w.prefix, line)
+}
@@ -0,0 +1,253 @@
+package site
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+	"github.com/ChrisWiegman/kana/internal/settings"
+)
+
+const migrateDatabaseFile = "migrate.sql"
+
+// countTablesScript Prints the row count of every table with the site's prefix as JSON. It runs through $wpdb so it
+// works with all database servers.
+const countTablesScript = `global $wpdb;
+$counts = array();
+foreach ( $wpdb->get_col( $wpdb->prepare( 'SHOW TABLES LIKE %s', $wpdb->esc_like( $wpdb->base_prefix ) . '%' ) ) as $table ) {
+	$counts[ $table ] = (int) $wpdb->get_var( "SELECT COUNT(*) FROM ` + "`$table`" + `" );
+}
+echo json_encode( $counts );`
+
+// MigrateDatabase Moves the site's content to a different database server, verifying every table made it across.
+func (s *Site) MigrateDatabase(database, databaseVersion string, consoleOutput *console.Console) error {
+	currentDatabase, err := s.getCurrentDatabase()
+	if err != nil {
+		return err
+	}
+
+	if database == currentDatabase {
+		return fmt.Errorf("the site is already using %s", database)
+	}
+
+	if databaseVersion == "" {
+		databaseVersion = settings.GetDefaultDatabaseVersion(database)
+	}
+
+	backupPath, err := s.getDatabaseBackupPath(currentDatabase)
+	if err != nil {
+		return err
+	}
+
+	consoleOutput.Println("Counting the rows in the current database.")
+
+	originalCounts, err := s.getTableRowCounts(consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	consoleOutput.Printf("Exporting the %s database.\n", currentDatabase)
+
+	err = s.exportDatabaseToSite(migrateDatabaseFile, consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	consoleOutput.Println("Stopping the site.")
+
+	err = s.StopSite()
+	if err != nil {
+		return err
+	}
+
+	err = s.setDatabaseSettings(database, databaseVersion)
+	if err != nil {
+		return err
+	}
+
+	err = s.moveDatabaseAside(currentDatabase, backupPath)
+	if err != nil {
+		return err
+	}
+
+	err = s.startSite(consoleOutput)
+	if err != nil {
+		return migrateError(err, backupPath)
+	}
+
+	consoleOutput.Printf("Importing the database into %s.\n", database)
+
+	err = s.importDatabaseFromSite(migrateDatabaseFile, false, "", consoleOutput)
+	if err != nil {
+		return migrateError(err, backupPath)
+	}
+
+	consoleOutput.Println("Verifying the migrated database.")
+
+	migratedCounts, err := s.getTableRowCounts(consoleOutput)
+	if err != nil {
+		return migrateError(err, backupPath)
+	}
+
+	err = compareTableRowCounts(originalCounts, migratedCounts)
+	if err != nil {
+		return migrateError(err, backupPath)
+	}
+
+	err = os.Remove(filepath.Join(s.settings.Get("siteDirectory"), migrateDatabaseFile))
+	if err != nil {
+		return err
+	}
+
+	// Named sites don't have a config file so the new database only lasts until the site is started again without it
+	if s.settings.GetBool("isNamed") {
+		consoleOutput.Warn(
+			fmt.Sprintf(
+				"Named sites don't save their settings. Use `kana start --name=%s --database=%s` to keep using %s.",
+				s.settings.Get("name"),
+				database,
+				database))
+
+		return nil
+	}
+
+	return s.settings.WriteLocalSettings(map[string]interface{}{
+		"database":        database,
+		"databaseVersion": databaseVersion,
+	})
+}
+
+// compareTableRowCounts Returns an error listing every table whose row count changed.
+func compareTableRowCounts(original, migrated map[string]int) error {
+	mismatches := []string{}
+
+	for table, count := range original {
+		migratedCount, ok := migrated[table]
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("%s (missing)", table))
+			continue
+		}
+
+		if migratedCount != count {
+			mismatches = append(mismatches, fmt.Sprintf("%s (%d rows, expected %d)", table, migratedCount, count))
+		}
+	}
+
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	sort.Strings(mismatches)
+
+	return fmt.Errorf("the migrated database doesn't match the original: %s", strings.Join(mismatches, ", "))
+}
+
+// getCurrentDatabase Returns the database server the running site is using.
+func (s *Site) getCurrentDatabase() (string, error) {
+	isUsingSQLite, err := s.isUsingSQLite()
+	if err != nil {
+		return "", err
+	}
+
+	if isUsingSQLite {
+		return "sqlite", nil
+	}
+
+	return s.settings.Get("database"), nil
+}
+
+// getDatabaseBackupPath Returns where the current database's files will be kept after the migration.
+func (s *Site) getDatabaseBackupPath(database string) (string, error) {
+	if database == "sqlite" {
+		// SQLite keeps its data in wp-content and the new server won't touch it
+		return s.getSQLiteDatabaseFile()
+	}
+
+	databaseDirectory, err := s.getDatabaseDirectory()
+	if err != nil {
+		return "", err
+	}
+
+	backupPath := fmt.Sprintf("%s-%s", databaseDirectory, database)
+
+	exists, err := helpers.PathExists(backupPath)
+	if err != nil {
+		return "", err
+	}
+
+	if exists {
+		return "", fmt.Errorf(
+			"a previous %s database has already been saved to %s. Please remove it before migrating the site again",
+			database,
+			backupPath)
+	}
+
+	return backupPath, nil
+}
+
+// getTableRowCounts Returns the number of rows in each of the site's tables.
+func (s *Site) getTableRowCounts(consoleOutput *console.Console) (map[string]int, error) {
+	code, output, err := s.WPCli([]string{"eval", countTablesScript}, false, consoleOutput)
+	if err != nil || code != 0 {
+		return nil, wpCliError("unable to count the database rows", err, output)
+	}
+
+	// Only the last line is our JSON. Anything before it is a PHP notice or warning.
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	counts := map[string]int{}
+
+	err = json.Unmarshal([]byte(lines[len(lines)-1]), &counts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the database row counts: %s", err)
+	}
+
+	return counts, nil
+}
+
+// moveDatabaseAside Moves the stopped site's database files out of the way so the new server starts with an empty database.
+func (s *Site) moveDatabaseAside(database, backupPath string) error {
+	if database != "sqlite" {
+		databaseDirectory, err := s.getDatabaseDirectory()
+		if err != nil {
+			return err
+		}
+
+		return os.Rename(databaseDirectory, backupPath)
+	}
+
+	// The SQLite drop-in would keep WordPress from connecting to the new server
+	wordPressDirectory, err := s.getWordPressDirectory()
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filepath.Join(wordPressDirectory, "wp-content", "db.php"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// setDatabaseSettings Switches the site's database settings to the new server.
+func (s *Site) setDatabaseSettings(database, databaseVersion string) error {
+	err := s.settings.Set("database", database)
+	if err != nil {
+		return err
+	}
+
+	if database == "sqlite" {
+		return nil
+	}
+
+	return s.settings.Set("databaseVersion", databaseVersion)
+}
+
+func migrateError(err error, backupPath string) error {
+	return fmt.Errorf("%s. Your original database has been kept at %s", err, backupPath)
+}
@@ -0,0 +1,143 @@
+package site
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/settings"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+// getLocalMounts Returns the parsed entries of the mounts setting.
+func (s *Site) getLocalMounts() ([]settings.Mount, error) {
+	localMounts := []settings.Mount{}
+
+	for _, entry := range s.settings.GetSlice("mounts") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		localMount, err := settings.ParseMount(entry)
+		if err != nil {
+			return localMounts, err
+		}
+
+		localMounts = append(localMounts, localMount)
+	}
+
+	return localMounts, nil
+}
+
+// getLocalMountVolumes Maps the directories in the mounts setting into the matching wp-content folders.
+func (s *Site) getLocalMountVolumes(appDir string) ([]mount.Mount, error) {
+	mountVolumes := []mount.Mount{}
+
+	localMounts, err := s.getLocalMounts()
+	if err != nil {
+		return mountVolumes, err
+	}
+
+	loaders := []string{}
+
+	for _, localMount := range localMounts {
+		source, err := s.resolveLocalPath(localMount.Path)
+		if err != nil {
+			return mountVolumes, err
+		}
+
+		err = os.MkdirAll(
+			filepath.Join(appDir, "wp-content", localMount.Type+"s", localMount.Slug),
+			os.FileMode(defaultDirPermissions))
+		if err != nil {
+			return mountVolumes, err
+		}
+
+		// WordPress doesn't look in folders for must-use plugins so they need a loader
+		if localMount.Type == "mu-plugin" {
+			err = settings.EnsureMUPluginLoader(appDir, localMount.Slug)
+			if err != nil {
+				return mountVolumes, err
+			}
+
+			loaders = append(loaders, settings.GetMUPluginLoaderName(localMount.Slug))
+		}
+
+		mountVolumes = append(mountVolumes, mount.Mount{
+			Type:   mount.TypeBind,
+			Source: source,
+			Target: filepath.Join("/var/www/html/wp-content", localMount.Type+"s", localMount.Slug),
+		})
+	}
+
+	return mountVolumes, removeStaleLoaders(appDir, loaders)
+}
+
+// removeStaleLoaders Removes the loaders of must-use plugin folders that are no longer mounted.
+func removeStaleLoaders(appDir string, loaders []string) error {
+	existingLoaders, err := filepath.Glob(filepath.Join(appDir, "wp-content", "mu-plugins", settings.GetMUPluginLoaderName("*")))
+	if err != nil {
+		return err
+	}
+
+	for _, existingLoader := range existingLoaders {
+		isCurrent := false
+
+		for _, loader := range loaders {
+			if filepath.Base(existingLoader) == loader {
+				isCurrent = true
+			}
+		}
+
+		if !isCurrent {
+			err = os.Remove(existingLoader)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeLinkMounts Records the local directories mounted into the site so Kana finds the site from any of them.
+func (s *Site) writeLinkMounts() error {
+	directories := []string{}
+
+	localMounts, err := s.getLocalMounts()
+	if err != nil {
+		return err
+	}
+
+	for _, localMount := range localMounts {
+		directory, err := s.resolveLocalPath(localMount.Path)
+		if err != nil {
+			return err
+		}
+
+		directories = append(directories, directory)
+	}
+
+	for _, kind := range []string{"plugin", "theme"} {
+		extensions, err := s.getExtensions(kind)
+		if err != nil {
+			return err
+		}
+
+		for _, extension := range extensions {
+			if extension.Source != settings.ExtensionSourcePath {
+				continue
+			}
+
+			directory, err := s.resolveLocalPath(extension.Path)
+			if err != nil {
+				return err
+			}
+
+			directories = append(directories, directory)
+		}
+	}
+
+	return settings.WriteLinkMounts(s.settings.Get("siteDirectory"), directories)
+}
@@ -0,0 +1,215 @@
+package site
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/docker"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+	"github.com/ChrisWiegman/kana/internal/settings"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+const (
+	phpConfDirectory = "/usr/local/etc/php/conf.d"
+	// PHP loads conf.d alphabetically so these come after the image's own files and the project's file wins.
+	phpIniFile        = "zz-kana.ini"
+	phpProjectIniFile = "zzz-kana-project.ini"
+)
+
+// defaultPHPIni are the directives Kana sets on every site unless the phpIni setting overrides them.
+var defaultPHPIni = map[string]string{
+	"upload_max_filesize": "200M",
+	"post_max_size":       "200M",
+}
+
+// phpExtensionPackages are the Debian packages needed to build extensions that depend on system libraries.
+var phpExtensionPackages = map[string][]string{
+	"bz2":       {"libbz2-dev"},
+	"gd":        {"libfreetype6-dev", "libjpeg-dev", "libpng-dev", "libwebp-dev"},
+	"gmp":       {"libgmp-dev"},
+	"imagick":   {"libmagickwand-dev"},
+	"intl":      {"libicu-dev"},
+	"ldap":      {"libldap2-dev"},
+	"memcached": {"libmemcached-dev", "libssl-dev", "zlib1g-dev"},
+	"mongodb":   {"libssl-dev"},
+	"pdo_pgsql": {"libpq-dev"},
+	"pgsql":     {"libpq-dev"},
+	"soap":      {"libxml2-dev"},
+	"sodium":    {"libsodium-dev"},
+	"tidy":      {"libtidy-dev"},
+	"xsl":       {"libxslt1-dev"},
+	"zip":       {"libzip-dev"},
+}
+
+// commonPHPExtensions are built into every Kana WordPress image in addition to the ones the official image includes.
+var commonPHPExtensions = []string{"pdo_mysql", "redis"}
+
+// wordPressImage holds the values used to render the embedded WordPress Dockerfile.
+type wordPressImage struct {
+	PHPVersion    string
+	XdebugVersion string
+	Extensions    []string
+	Packages      []string
+}
+
+// ensureWordPressImage Builds the site's WordPress image, with Xdebug and any extra extensions, if it isn't already cached.
+func (s *Site) ensureWordPressImage(consoleOutput *console.Console) error {
+	imageName := s.getWordPressImage()
+
+	extensions := append([]string{}, commonPHPExtensions...)
+
+	for _, extension := range s.getPHPExtensions() {
+		if !helpers.IsValidString(extension, extensions) {
+			extensions = append(extensions, extension)
+		}
+	}
+
+	imageValues := wordPressImage{
+		PHPVersion: s.settings.Get("php"),
+		Extensions: extensions,
+		Packages:   []string{},
+	}
+
+	// Xdebug 3.2 and later need PHP 8
+	if strings.HasPrefix(imageValues.PHPVersion, "7.") {
+		imageValues.XdebugVersion = "-3.1.6"
+	}
+
+	for _, extension := range extensions {
+		for _, phpPackage := range phpExtensionPackages[extension] {
+			if !helpers.IsValidString(phpPackage, imageValues.Packages) {
+				imageValues.Packages = append(imageValues.Packages, phpPackage)
+			}
+		}
+	}
+
+	var dockerfile strings.Builder
+
+	tmpl := template.Must(template.New("wordpressDockerfile").Parse(settings.WordPressDockerfile))
+
+	err := tmpl.Execute(&dockerfile, imageValues)
+	if err != nil {
+		return err
+	}
+
+	return s.dockerClient.EnsureBuiltImage(
+		imageName,
+		dockerfile.String(),
+		s.settings.Get("appDirectory"),
+		s.settings.GetInt("updateInterval"),
+		consoleOutput)
+}
+
+// getPHPExtensions Returns the site's extra PHP extensions sorted so the same set always uses the same image.
+func (s *Site) getPHPExtensions() []string {
+	extensions := []string{}
+
+	for _, extension := range s.settings.GetSlice("phpExtensions") {
+		extension = strings.TrimSpace(extension)
+
+		if extension != "" && !helpers.IsValidString(extension, extensions) {
+			extensions = append(extensions, extension)
+		}
+	}
+
+	sort.Strings(extensions)
+
+	return extensions
+}
+
+// getPHPIniMounts Returns the php.ini files to mount into the site's PHP containers.
+func (s *Site) getPHPIniMounts() []mount.Mount {
+	phpMounts := []mount.Mount{}
+
+	projectIni := filepath.Join(s.settings.Get("workingDirectory"), ".kana", "php.ini")
+
+	hasProjectIni, err := helpers.PathExists(projectIni)
+	if err == nil && hasProjectIni {
+		phpMounts = append(phpMounts, mount.Mount{
+			Type:     mount.TypeBind,
+			Source:   projectIni,
+			Target:   path.Join(phpConfDirectory, phpProjectIniFile),
+			ReadOnly: true,
+		})
+	}
+
+	siteIni := filepath.Join(s.settings.Get("siteDirectory"), "php", phpIniFile)
+
+	hasSiteIni, err := helpers.PathExists(siteIni)
+	if err == nil && hasSiteIni {
+		phpMounts = append(phpMounts, mount.Mount{
+			Type:     mount.TypeBind,
+			Source:   siteIni,
+			Target:   path.Join(phpConfDirectory, phpIniFile),
+			ReadOnly: true,
+		})
+	}
+
+	return phpMounts
+}
+
+// getWordPressImage Returns the locally built image for the WordPress container.
+// Sites with the same PHP version and extra extensions share an image.
+func (s *Site) getWordPressImage() string {
+	imageName := fmt.Sprintf("%swordpress:php%s-xdebug", docker.LocalImagePrefix, s.settings.Get("php"))
+
+	extensions := s.getPHPExtensions()
+
+	if len(extensions) == 0 {
+		return imageName
+	}
+
+	hash := sha256.Sum256([]byte(strings.Join(extensions, ",")))
+
+	return fmt.Sprintf("%s-%s", imageName, hex.EncodeToString(hash[:])[:12])
+}
+
+// writePHPIni Writes Kana's defaults and the site's phpIni setting to the ini file mounted into the PHP containers.
+func (s *Site) writePHPIni() error {
+	phpIni := map[string]string{}
+
+	for key, value := range defaultPHPIni {
+		phpIni[key] = value
+	}
+
+	for key, value := range s.settings.GetPHPIni() {
+		phpIni[key] = value
+	}
+
+	keys := make([]string, 0, len(phpIni))
+
+	for key := range phpIni {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var contents strings.Builder
+
+	contents.WriteString("; Generated by Kana on every start. Use the phpIni setting or .kana/php.ini to change these values.\n")
+
+	for _, key := range keys {
+		fmt.Fprintf(&contents, "%s = %s\n", key, phpIni[key])
+	}
+
+	phpDirectory := filepath.Join(s.settings.Get("siteDirectory"), "php")
+
+	err := os.MkdirAll(phpDirectory, os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return err
+	}
+
+	_, filePerms := settings.GetDefaultFilePermissions()
+
+	return os.WriteFile(filepath.Join(phpDirectory, phpIniFile), []byte(contents.String()), os.FileMode(filePerms))
+}
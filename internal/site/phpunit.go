@@ -0,0 +1,262 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/docker"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+	"github.com/ChrisWiegman/kana/internal/settings"
+)
+
+const (
+	testDatabaseName = "wordpress_test"
+	testsDirectory   = "tests"
+)
+
+// wpTestsConfig is the wp-tests-config.php used by the WordPress core test library.
+const wpTestsConfig = `<?php
+define( 'ABSPATH', '/var/www/html/' );
+define( 'WP_DEFAULT_THEME', 'default' );
+define( 'WP_DEBUG', true );
+
+define( 'DB_NAME', '%s' );
+define( 'DB_USER', 'wordpress' );
+define( 'DB_PASSWORD', 'wordpress' );
+define( 'DB_HOST', '%s' );
+define( 'DB_CHARSET', 'utf8' );
+define( 'DB_COLLATE', '' );
+
+$table_prefix = 'wptests_';
+
+define( 'WP_TESTS_DOMAIN', 'example.org' );
+define( 'WP_TESTS_EMAIL', 'admin@example.org' );
+define( 'WP_TESTS_TITLE', 'Test Blog' );
+define( 'WP_PHP_BINARY', 'php' );
+define( 'WPLANG', '' );
+`
+
+// RunTests Runs the project's PHPUnit tests against the WordPress core test library and returns PHPUnit's exit code.
+func (s *Site) RunTests(args []string, junitFile string, consoleOutput *console.Console) (int64, error) {
+	if s.settings.Get("type") == DefaultType {
+		return 1, fmt.Errorf("the test command only works with plugin and theme sites")
+	}
+
+	isUsingSQLite, err := s.isUsingSQLite()
+	if err != nil {
+		return 1, err
+	}
+
+	if isUsingSQLite {
+		return 1, fmt.Errorf("the WordPress test library needs a MariaDB or MySQL database. Use 'kana db migrate' to switch databases")
+	}
+
+	phpunit := filepath.Join(s.settings.Get("workingDirectory"), "vendor", "bin", "phpunit")
+
+	hasPHPUnit, err := helpers.PathExists(phpunit)
+	if err != nil {
+		return 1, err
+	}
+
+	if !hasPHPUnit {
+		return 1, fmt.Errorf(
+			"unable to find vendor/bin/phpunit. Please add PHPUnit to your project with 'composer require --dev phpunit/phpunit'")
+	}
+
+	testLibrary, err := s.ensureTestLibrary(consoleOutput)
+	if err != nil {
+		return 1, err
+	}
+
+	err = s.ensureTestDatabase()
+	if err != nil {
+		return 1, err
+	}
+
+	command := []string{
+		"php",
+		"vendor/bin/phpunit",
+	}
+
+	if junitFile != "" {
+		var junitPath string
+
+		junitPath, err = s.getProjectPath(junitFile)
+		if err != nil {
+			return 1, err
+		}
+
+		command = append(command, "--log-junit", junitPath)
+	}
+
+	command = append(command, args...)
+
+	testContainer, err := s.getTestContainer(testLibrary, command)
+	if err != nil {
+		return 1, err
+	}
+
+	err = s.dockerClient.EnsureImage(testContainer.Image, s.settings.Get("appDirectory"), s.settings.GetInt("updateInterval"), consoleOutput)
+	if err != nil {
+		return 1, err
+	}
+
+	code, _, err := s.dockerClient.ContainerRunAndClean(&testContainer, true)
+
+	return code, err
+}
+
+// ensureTestDatabase Creates the test database in the site's database container if it doesn't already exist.
+func (s *Site) ensureTestDatabase() error {
+	client := "mariadb"
+
+	if s.settings.Get("database") == "mysql" {
+		client = "mysql"
+	}
+
+	command := fmt.Sprintf(
+		"%s -uroot -ppassword -e \"CREATE DATABASE IF NOT EXISTS %s; GRANT ALL PRIVILEGES ON %s.* TO 'wordpress'@'%%';\"",
+		client,
+		testDatabaseName,
+		testDatabaseName)
+
+	output, err := s.dockerClient.ContainerExec(fmt.Sprintf("kana-%s-database", s.settings.Get("name")), false, []string{command})
+	if err != nil {
+		return err
+	}
+
+	if output.ExitCode != 0 {
+		return fmt.Errorf("unable to create the test database: %s", strings.TrimSpace(output.StdErr))
+	}
+
+	return nil
+}
+
+// ensureTestLibrary Downloads the WordPress core test library matching the site's WordPress version and writes its config.
+// It returns the path of the library in the container.
+func (s *Site) ensureTestLibrary(consoleOutput *console.Console) (string, error) {
+	code, output, err := s.WPCli([]string{"core", "version"}, false, consoleOutput)
+	if err != nil || code != 0 {
+		return "", wpCliError("unable to determine the WordPress version", err, output)
+	}
+
+	version := strings.TrimSpace(output)
+
+	// The test library is tagged with full versions such as 6.7.0
+	if strings.Count(version, ".") == 1 {
+		version += ".0"
+	}
+
+	libraryName := fmt.Sprintf("wp-phpunit-%s", version)
+	testsPath := filepath.Join(s.settings.Get("siteDirectory"), testsDirectory)
+	libraryPath := filepath.Join(testsPath, libraryName)
+
+	hasLibrary, err := helpers.PathExists(filepath.Join(libraryPath, "includes", "bootstrap.php"))
+	if err != nil {
+		return "", err
+	}
+
+	if !hasLibrary {
+		consoleOutput.Printf("Downloading the WordPress %s test library.\n", version)
+
+		err = os.MkdirAll(testsPath, os.FileMode(defaultDirPermissions))
+		if err != nil {
+			return "", err
+		}
+
+		var file string
+
+		file, err = helpers.DownloadFile(
+			fmt.Sprintf("https://github.com/wp-phpunit/wp-phpunit/archive/refs/tags/%s.zip", version),
+			testsPath)
+		if err != nil {
+			return "", fmt.Errorf("unable to download the WordPress %s test library: %s", version, err)
+		}
+
+		err = helpers.UnZipFile(filepath.Join(testsPath, file), testsPath)
+		if err != nil {
+			return "", err
+		}
+
+		err = os.Remove(filepath.Join(testsPath, file))
+		if err != nil {
+			return "", err
+		}
+	}
+
+	_, filePerms := settings.GetDefaultFilePermissions()
+
+	err = os.WriteFile(
+		filepath.Join(libraryPath, "wp-tests-config.php"),
+		fmt.Appendf(nil, wpTestsConfig, testDatabaseName, fmt.Sprintf("kana-%s-database", s.settings.Get("name"))),
+		os.FileMode(filePerms))
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join("/Site", testsDirectory, libraryName), nil
+}
+
+// getProjectPath Returns the container path of a file given relative to the project directory.
+func (s *Site) getProjectPath(file string) (string, error) {
+	workingDirectory := s.settings.Get("workingDirectory")
+
+	if filepath.IsAbs(file) {
+		relativePath, err := filepath.Rel(workingDirectory, file)
+		if err != nil || strings.HasPrefix(relativePath, "..") {
+			return "", fmt.Errorf("the file, %s, must be inside the project directory, %s", file, workingDirectory)
+		}
+
+		file = relativePath
+	}
+
+	return path.Join(s.getProjectMountPath(), filepath.ToSlash(file)), nil
+}
+
+// getProjectMountPath Returns where the project is mounted in the site's containers.
+func (s *Site) getProjectMountPath() string {
+	projectType := "plugins"
+
+	if s.settings.Get("type") == "theme" {
+		projectType = "themes"
+	}
+
+	return path.Join("/var/www/html/wp-content", projectType, s.settings.Get("name"))
+}
+
+// getTestContainer Returns the container that runs PHPUnit from the project directory.
+func (s *Site) getTestContainer(testLibrary string, command []string) (docker.ContainerConfig, error) {
+	wordPressDirectory, err := s.getWordPressDirectory()
+	if err != nil {
+		return docker.ContainerConfig{}, err
+	}
+
+	appVolumes, err := s.getWordPressMounts(wordPressDirectory)
+	if err != nil {
+		return docker.ContainerConfig{}, err
+	}
+
+	appVolumes = append(appVolumes, s.getPHPIniMounts()...)
+
+	return docker.ContainerConfig{
+		Name:        fmt.Sprintf("kana-%s-phpunit", s.settings.Get("name")),
+		Image:       fmt.Sprintf("wordpress:cli-php%s", s.settings.Get("php")),
+		NetworkName: "kana",
+		HostName:    fmt.Sprintf("kana-%s-phpunit", s.settings.Get("name")),
+		Command:     command,
+		WorkingDir:  s.getProjectMountPath(),
+		Env: []string{
+			"IS_KANA_ENVIRONMENT=true",
+			fmt.Sprintf("WP_TESTS_DIR=%s", testLibrary),
+			fmt.Sprintf("WP_PHPUNIT__TESTS_CONFIG=%s", path.Join(testLibrary, "wp-tests-config.php")),
+		},
+		Labels: map[string]string{
+			"kana.site": s.settings.Get("name"),
+		},
+		Volumes: appVolumes,
+	}, nil
+}
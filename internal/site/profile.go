@@ -0,0 +1,283 @@
+package site
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/cachegrind"
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/settings"
+)
+
+const (
+	profilesDirectory  = "profiles"
+	profileWaitSeconds = 10
+	profilePollDelay   = 250 * time.Millisecond
+	profileAttempts    = 5
+)
+
+// How long to wait for Xdebug to start a profile before sending the request again.
+var profileStartTimeout = 2 * time.Second
+
+// ProfileResult holds the files Xdebug wrote for a profiled request and the parsed profile.
+type ProfileResult struct {
+	URL        string              `json:"url"`
+	StatusCode int                 `json:"statusCode"`
+	File       string              `json:"file"`
+	TraceFile  string              `json:"traceFile,omitempty"`
+	Profile    *cachegrind.Profile `json:"profile"`
+}
+
+// ProfileRequest Profiles a single request to the given path of the site with Xdebug, optionally tracing it as well.
+// Xdebug is returned to its previous state afterwards.
+func (s *Site) ProfileRequest(urlPath string, trace bool, consoleOutput *console.Console) (ProfileResult, error) {
+	result := ProfileResult{
+		URL: fmt.Sprintf("%s/%s", s.settings.GetURL(), strings.TrimPrefix(urlPath, "/")),
+	}
+
+	if !s.hasXdebugMount() {
+		return result, fmt.Errorf(
+			"this site was started by an older version of Kana. Please restart it with `kana stop` and `kana start` to profile it")
+	}
+
+	profilesPath := filepath.Join(s.settings.Get("siteDirectory"), profilesDirectory)
+
+	err := os.MkdirAll(profilesPath, os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return result, err
+	}
+
+	// Apache runs as www-data, which doesn't own the folder on Linux
+	err = os.Chmod(profilesPath, 0o777) //nolint:gosec
+	if err != nil {
+		return result, err
+	}
+
+	existingFiles, err := getProfileFiles(profilesPath)
+	if err != nil {
+		return result, err
+	}
+
+	previousIni, err := os.ReadFile(s.getXdebugMount().Source)
+	if err != nil {
+		return result, err
+	}
+
+	mode := "profile"
+
+	if trace {
+		mode = "profile,trace"
+	}
+
+	err = s.StartXdebug(mode, consoleOutput)
+	if err == nil {
+		result.StatusCode, err = sendProfiledRequest(result.URL, profilesPath, existingFiles)
+	}
+
+	// Always put Xdebug back the way we found it
+	restoreErr := s.restoreXdebugIni(previousIni)
+	if err != nil {
+		return result, err
+	}
+
+	if restoreErr != nil {
+		return result, restoreErr
+	}
+
+	profileFile, traceFile, err := waitForProfileFiles(profilesPath, existingFiles, trace)
+	if err != nil {
+		return result, err
+	}
+
+	result.File = profileFile
+	result.TraceFile = traceFile
+
+	file, err := os.Open(profileFile)
+	if err != nil {
+		return result, err
+	}
+
+	defer file.Close()
+
+	var reader io.Reader = file
+
+	// Profiles written before compression was turned off in Kana's ini are gzipped
+	if strings.HasSuffix(profileFile, ".gz") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return result, err
+		}
+
+		defer gzipReader.Close()
+
+		reader = gzipReader
+	}
+
+	result.Profile, err = cachegrind.Parse(reader)
+
+	return result, err
+}
+
+// getProfileFiles Returns the names of the files already in the profiles folder.
+func getProfileFiles(profilesPath string) (map[string]bool, error) {
+	files := map[string]bool{}
+
+	entries, err := os.ReadDir(profilesPath)
+	if err != nil {
+		return files, err
+	}
+
+	for _, entry := range entries {
+		files[entry.Name()] = true
+	}
+
+	return files, nil
+}
+
+// restoreXdebugIni Writes back the Xdebug ini file from before profiling and reloads Apache.
+func (s *Site) restoreXdebugIni(previousIni []byte) error {
+	_, filePerms := settings.GetDefaultFilePermissions()
+
+	err := os.WriteFile(s.getXdebugMount().Source, previousIni, os.FileMode(filePerms))
+	if err != nil {
+		return err
+	}
+
+	return s.reloadApache()
+}
+
+// sendProfiledRequest Sends the request until Xdebug profiles one. Apache's graceful reload returns before its workers
+// have picked up the new ini so the first requests may be handled without Xdebug.
+func sendProfiledRequest(profileURL, profilesPath string, existingFiles map[string]bool) (int, error) {
+	statusCode := 0
+
+	for range profileAttempts {
+		var err error
+
+		statusCode, err = sendProfileRequest(profileURL)
+		if err != nil {
+			return statusCode, err
+		}
+
+		// Xdebug creates the profile as the request starts, so a new file means the request was profiled
+		for range int(profileStartTimeout / profilePollDelay) {
+			hasProfile, err := hasNewProfile(profilesPath, existingFiles)
+			if err != nil {
+				return statusCode, err
+			}
+
+			if hasProfile {
+				return statusCode, nil
+			}
+
+			time.Sleep(profilePollDelay)
+		}
+	}
+
+	// Leave reporting the missing profile to waitForProfileFiles
+	return statusCode, nil
+}
+
+// hasNewProfile Returns true if a profile has been added to the profiles folder.
+func hasNewProfile(profilesPath string, existingFiles map[string]bool) (bool, error) {
+	entries, err := os.ReadDir(profilesPath)
+	if err != nil {
+		return false, err
+	}
+
+	for _, entry := range entries {
+		if !existingFiles[entry.Name()] && strings.HasPrefix(entry.Name(), "cachegrind.out.") {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// sendProfileRequest Requests the URL with the Xdebug trigger cookie and returns the response's status code.
+func sendProfileRequest(profileURL string) (int, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, profileURL, http.NoBody)
+	if err != nil {
+		return 0, err
+	}
+
+	req.AddCookie(&http.Cookie{Name: "XDEBUG_TRIGGER", Value: "kana"})
+
+	// Ignore SSL check as we're using our self-signed cert for development
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+
+	return resp.StatusCode, nil
+}
+
+// waitForProfileFiles Waits for Xdebug to finish writing the files for the request, which happens after the response is sent.
+func waitForProfileFiles(profilesPath string, existingFiles map[string]bool, trace bool) (profileFile, traceFile string, err error) {
+	sizes := map[string]int64{}
+
+	for range profileWaitSeconds * int(time.Second/profilePollDelay) {
+		time.Sleep(profilePollDelay)
+
+		entries, readErr := os.ReadDir(profilesPath)
+		if readErr != nil {
+			return "", "", readErr
+		}
+
+		profileFile, traceFile = "", ""
+		isStable := true
+
+		for _, entry := range entries {
+			if existingFiles[entry.Name()] {
+				continue
+			}
+
+			info, infoErr := entry.Info()
+			if infoErr != nil {
+				return "", "", infoErr
+			}
+
+			if sizes[entry.Name()] != info.Size() || info.Size() == 0 {
+				isStable = false
+			}
+
+			sizes[entry.Name()] = info.Size()
+
+			switch {
+			case strings.HasPrefix(entry.Name(), "cachegrind.out."):
+				profileFile = filepath.Join(profilesPath, entry.Name())
+			case strings.HasPrefix(entry.Name(), "trace."):
+				traceFile = filepath.Join(profilesPath, entry.Name())
+			}
+		}
+
+		if isStable && profileFile != "" && (!trace || traceFile != "") {
+			return profileFile, traceFile, nil
+		}
+	}
+
+	if profileFile == "" {
+		return "", "", fmt.Errorf("xdebug didn't write a profile. Check that the page exists and that PHP handled the request")
+	}
+
+	return profileFile, traceFile, nil
+}
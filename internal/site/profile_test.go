@@ -0,0 +1,69 @@
+package site
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendProfiledRequest(t *testing.T) {
+	profileStartTimeout = profilePollDelay
+
+	var tests = []struct {
+		name             string
+		profiledRequest  int
+		expectedRequests int
+	}{
+		{
+			name:             "First request profiled",
+			profiledRequest:  1,
+			expectedRequests: 1,
+		},
+		{
+			name:             "Profiled after the reload",
+			profiledRequest:  3,
+			expectedRequests: 3,
+		},
+		{
+			name:             "Never profiled",
+			profiledRequest:  0,
+			expectedRequests: profileAttempts,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			profilesPath := t.TempDir()
+
+			assert.NoError(t, os.WriteFile(filepath.Join(profilesPath, "cachegrind.out.1"), []byte{}, 0600))
+
+			existingFiles, err := getProfileFiles(profilesPath)
+			assert.NoError(t, err)
+
+			requests := 0
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+
+				cookie, err := r.Cookie("XDEBUG_TRIGGER")
+				assert.NoError(t, err)
+				assert.Equal(t, "kana", cookie.Value)
+
+				if requests == test.profiledRequest {
+					assert.NoError(t, os.WriteFile(filepath.Join(profilesPath, "cachegrind.out.2"), []byte{}, 0600))
+				}
+			}))
+			defer server.Close()
+
+			statusCode, err := sendProfiledRequest(server.URL, profilesPath, existingFiles)
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, statusCode)
+			assert.Equal(t, test.expectedRequests, requests)
+		})
+	}
+}
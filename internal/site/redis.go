@@ -0,0 +1,124 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/docker"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+const redisDropInSignature = "Redis Object Cache Drop-In"
+
+// FlushRedis Empties the site's Redis object cache if Redis is running.
+func (s *Site) FlushRedis() error {
+	if !s.isRedisRunning() {
+		return nil
+	}
+
+	output, err := s.dockerClient.ContainerExec(s.getRedisContainerName(), false, []string{"redis-cli FLUSHALL"})
+	if err != nil {
+		return err
+	}
+
+	if output.ExitCode != 0 {
+		return fmt.Errorf("unable to flush redis: %s", strings.TrimSpace(output.StdErr))
+	}
+
+	return nil
+}
+
+func (s *Site) getRedisContainer() docker.ContainerConfig {
+	redisContainer := docker.ContainerConfig{
+		Name:        s.getRedisContainerName(),
+		Image:       "redis",
+		NetworkName: "kana",
+		HostName:    s.getRedisContainerName(),
+		Env:         []string{},
+		Volumes:     []mount.Mount{},
+		Ports: []docker.ExposedPorts{
+			{Port: "6379", Protocol: "tcp"},
+		},
+		Labels: map[string]string{
+			"kana.type": "redis",
+			"kana.site": s.settings.Get("name"),
+		},
+	}
+
+	return redisContainer
+}
+
+// getRedisConfig Returns the wp-config.php code pointing the object cache at the site's Redis container.
+func (s *Site) getRedisConfig() string {
+	return fmt.Sprintf("define( 'WP_REDIS_HOST', '%s' );", s.getRedisContainerName())
+}
+
+func (s *Site) getRedisContainerName() string {
+	return fmt.Sprintf("kana-%s-redis", s.settings.Get("name"))
+}
+
+func (s *Site) isRedisRunning() bool {
+	containers, err := s.dockerClient.ContainerList(s.settings.Get("name"))
+	if err != nil {
+		return false
+	}
+
+	for i := range containers {
+		if containers[i].Labels["kana.type"] == "redis" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// maybeSetupRedisCache Installs the Redis Object Cache drop-in when Redis is enabled and removes it when it isn't.
+func (s *Site) maybeSetupRedisCache(consoleOutput *console.Console) error {
+	wordPressDirectory, err := s.getWordPressDirectory()
+	if err != nil {
+		return err
+	}
+
+	dropIn, err := os.ReadFile(filepath.Join(wordPressDirectory, "wp-content", "object-cache.php"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	hasDropIn := strings.Contains(string(dropIn), redisDropInSignature)
+
+	if !s.settings.GetBool("redis") {
+		if !hasDropIn {
+			return nil
+		}
+
+		// Without its server the drop-in would only slow the site down
+		code, _, err := s.WPCli([]string{"redis", "disable"}, false, consoleOutput)
+		if err != nil || code != 0 {
+			consoleOutput.Warn("Unable to disable the Redis object cache. Remove wp-content/object-cache.php to disable it manually.")
+		}
+
+		return nil
+	}
+
+	consoleOutput.Println("Installing and configuring the Redis object cache.")
+
+	code, output, err := s.WPCli([]string{"plugin", "install", "redis-cache", "--activate"}, false, consoleOutput)
+	if err != nil || code != 0 {
+		return wpCliError("unable to install the redis-cache plugin", err, output)
+	}
+
+	if hasDropIn {
+		return nil
+	}
+
+	code, output, err = s.WPCli([]string{"redis", "enable"}, false, consoleOutput)
+	if err != nil || code != 0 {
+		return wpCliError("unable to enable the redis object cache", err, output)
+	}
+
+	return nil
+}
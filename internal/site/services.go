@@ -0,0 +1,162 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/docker"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+// getServiceContainers Returns the container configs for the extra services declared in the site's config.
+func (s *Site) getServiceContainers() ([]docker.ContainerConfig, error) {
+	services := s.settings.GetServices()
+	serviceContainers := []docker.ContainerConfig{}
+
+	names := make([]string, 0, len(services))
+
+	for name := range services {
+		names = append(names, name)
+	}
+
+	// Start services in a predictable order
+	sort.Strings(names)
+
+	for _, name := range names {
+		service := services[name]
+		containerName := fmt.Sprintf("kana-%s-%s", s.settings.Get("name"), name)
+
+		volumes, err := s.getServiceVolumes(service.Volumes)
+		if err != nil {
+			return serviceContainers, err
+		}
+
+		serviceContainer := docker.ContainerConfig{
+			Name:        containerName,
+			Image:       service.Image,
+			NetworkName: "kana",
+			HostName:    containerName,
+			Command:     service.Command,
+			Env:         service.Env,
+			Ports:       getServicePorts(service.Ports),
+			Volumes:     volumes,
+			Labels: map[string]string{
+				"kana.type":    "service",
+				"kana.service": name,
+				"kana.site":    s.settings.Get("name"),
+			},
+		}
+
+		if service.HTTPPort != "" {
+			for label, value := range s.getServiceTraefikLabels(name, service.HTTPPort) {
+				serviceContainer.Labels[label] = value
+			}
+		}
+
+		serviceContainers = append(serviceContainers, serviceContainer)
+	}
+
+	return serviceContainers, nil
+}
+
+// getServicePorts Converts ports such as "6379" or "6831/udp" into the ports to expose on the container.
+func getServicePorts(ports []string) []docker.ExposedPorts {
+	exposedPorts := []docker.ExposedPorts{}
+
+	for _, port := range ports {
+		protocol := "tcp"
+
+		if portParts := strings.SplitN(port, "/", 2); len(portParts) == 2 {
+			port = portParts[0]
+			protocol = portParts[1]
+		}
+
+		exposedPorts = append(exposedPorts, docker.ExposedPorts{Port: port, Protocol: protocol})
+	}
+
+	return exposedPorts
+}
+
+// getServiceTraefikLabels Returns the labels needed to expose a service at <service>-<site domain> through Traefik.
+func (s *Site) getServiceTraefikLabels(name, port string) map[string]string {
+	routerName := fmt.Sprintf("wordpress-%s-%s", s.settings.Get("name"), name)
+	hostRule := fmt.Sprintf("Host(`%s-%s`)", name, s.settings.GetDomain())
+
+	return map[string]string{
+		"traefik.enable": "true",
+		fmt.Sprintf("traefik.http.routers.%s-http.entrypoints", routerName):                                        "web",
+		fmt.Sprintf("traefik.http.routers.%s-http.rule", routerName):                                               hostRule,
+		fmt.Sprintf("traefik.http.routers.%s.entrypoints", routerName):                                             "websecure",
+		fmt.Sprintf("traefik.http.routers.%s.rule", routerName):                                                    hostRule,
+		fmt.Sprintf("traefik.http.routers.%s.tls", routerName):                                                     "true",
+		fmt.Sprintf("traefik.http.services.%s-%s-http-svc.loadbalancer.server.port", s.settings.Get("name"), name): port,
+	}
+}
+
+// getServiceVolumes Bind mounts each "<local path>:<container path>" volume, resolving relative paths from the working directory.
+func (s *Site) getServiceVolumes(volumes []string) ([]mount.Mount, error) {
+	serviceVolumes := []mount.Mount{}
+
+	for _, volume := range volumes {
+		volumeParts := strings.SplitN(volume, ":", 2)
+
+		source := volumeParts[0]
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(s.settings.Get("workingDirectory"), source)
+		}
+
+		err := os.MkdirAll(source, os.FileMode(defaultDirPermissions))
+		if err != nil {
+			return serviceVolumes, err
+		}
+
+		serviceVolumes = append(serviceVolumes, mount.Mount{
+			Type:   mount.TypeBind,
+			Source: source,
+			Target: volumeParts[1],
+		})
+	}
+
+	return serviceVolumes, nil
+}
+
+// getRunningServices Returns the container names of the site's running services keyed by service name.
+func (s *Site) getRunningServices(siteName string) (map[string]string, error) {
+	runningServices := map[string]string{}
+
+	containers, err := s.dockerClient.ContainerList(siteName)
+	if err != nil {
+		return runningServices, err
+	}
+
+	for i := range containers {
+		if containers[i].Labels["kana.type"] != "service" || len(containers[i].Names) == 0 {
+			continue
+		}
+
+		runningServices[containers[i].Labels["kana.service"]] = strings.Trim(containers[i].Names[0], "/")
+	}
+
+	return runningServices, nil
+}
+
+// stopServices Stops all of the site's running services, including any that have since been removed from the config.
+func (s *Site) stopServices() error {
+	runningServices, err := s.getRunningServices(s.settings.Get("name"))
+	if err != nil {
+		return err
+	}
+
+	for _, containerName := range runningServices {
+		_, err = s.dockerClient.ContainerStop(containerName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package site
+
+import (
+	"fmt"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+)
+
+// shellCommand opens bash where the image has it and falls back to sh everywhere else.
+var shellCommand = []string{
+	"sh",
+	"-c",
+	"if command -v bash > /dev/null; then exec bash; else exec sh; fi",
+}
+
+// GetShellServices Returns the services a shell can be opened in.
+func GetShellServices() []string {
+	return []string{"wordpress", "database", "cli"}
+}
+
+// OpenShell Opens an interactive shell in one of the site's containers and returns the shell's exit code.
+func (s *Site) OpenShell(service string, consoleOutput *console.Console) (int64, error) {
+	switch service {
+	case "", "wordpress":
+		code, err := s.dockerClient.ContainerExecInteractive(fmt.Sprintf("kana-%s-wordpress", s.settings.Get("name")), false, shellCommand)
+		return int64(code), err
+	case "database":
+		isUsingSQLite, err := s.isUsingSQLite()
+		if err != nil {
+			return 1, err
+		}
+
+		if isUsingSQLite {
+			return 1, fmt.Errorf("this site uses SQLite so there is no database container to open a shell in")
+		}
+
+		code, err := s.dockerClient.ContainerExecInteractive(fmt.Sprintf("kana-%s-database", s.settings.Get("name")), false, shellCommand)
+
+		return int64(code), err
+	case "cli":
+		cliContainer, err := s.getCliContainer(shellCommand)
+		if err != nil {
+			return 1, err
+		}
+
+		cliContainer.WorkingDir = "/var/www/html"
+
+		err = s.dockerClient.EnsureImage(cliContainer.Image, s.settings.Get("appDirectory"), s.settings.GetInt("updateInterval"), consoleOutput)
+		if err != nil {
+			return 1, err
+		}
+
+		code, _, err := s.dockerClient.ContainerRunAndClean(&cliContainer, true)
+
+		return code, err
+	default:
+		return 1, fmt.Errorf("unknown service %s. A shell can be opened in the wordpress, database or cli container", service)
+	}
+}
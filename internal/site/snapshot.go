@@ -0,0 +1,243 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+)
+
+// SnapshotManifest holds the information saved alongside each database snapshot.
+type SnapshotManifest struct {
+	Name             string    `json:"name"`
+	Site             string    `json:"site"`
+	Domain           string    `json:"domain"`
+	Created          time.Time `json:"created"`
+	WordPressVersion string    `json:"wordpressVersion"`
+	PHPVersion       string    `json:"phpVersion"`
+	Database         string    `json:"database"`
+	DatabaseVersion  string    `json:"databaseVersion"`
+	Plugins          []string  `json:"plugins"`
+}
+
+const snapshotTempFile = "snapshot.sql"
+
+var validSnapshotName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// DeleteSnapshot Removes a saved snapshot and its manifest.
+func (s *Site) DeleteSnapshot(name string) error {
+	snapshotDirectory := s.getSnapshotDirectory(s.settings.Get("name"))
+
+	_, err := s.readSnapshotManifest(snapshotDirectory, name)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filepath.Join(snapshotDirectory, name+".sql.gz"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return os.Remove(filepath.Join(snapshotDirectory, name+".json"))
+}
+
+// GetSnapshotList Returns the manifests of all snapshots saved for the site, oldest first.
+func (s *Site) GetSnapshotList() ([]SnapshotManifest, error) {
+	snapshots := []SnapshotManifest{}
+	snapshotDirectory := s.getSnapshotDirectory(s.settings.Get("name"))
+
+	files, err := os.ReadDir(snapshotDirectory)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return snapshots, nil
+		}
+
+		return snapshots, err
+	}
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+
+		manifest, err := s.readSnapshotManifest(snapshotDirectory, strings.TrimSuffix(file.Name(), ".json"))
+		if err != nil {
+			return snapshots, err
+		}
+
+		snapshots = append(snapshots, manifest)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Created.Before(snapshots[j].Created)
+	})
+
+	return snapshots, nil
+}
+
+// RestoreSnapshot Restores a saved snapshot, from the current site or another site, into the site's database.
+func (s *Site) RestoreSnapshot(name, sourceSite string, preserve bool, replaceDomain string, consoleOutput *console.Console) error {
+	if sourceSite == "" {
+		sourceSite = s.settings.Get("name")
+	}
+
+	snapshotDirectory := s.getSnapshotDirectory(helpers.SanitizeSiteName(sourceSite))
+
+	manifest, err := s.readSnapshotManifest(snapshotDirectory, name)
+	if err != nil {
+		return err
+	}
+
+	// Snapshots taken on another site need that site's domain replaced with our own
+	if replaceDomain == "" && manifest.Domain != s.settings.GetDomain() {
+		replaceDomain = manifest.Domain
+	}
+
+	tempFile := filepath.Join(s.settings.Get("siteDirectory"), snapshotTempFile)
+
+	err = helpers.GunzipFile(filepath.Join(snapshotDirectory, name+".sql.gz"), tempFile)
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tempFile)
+
+	return s.importDatabaseFromSite(snapshotTempFile, preserve, replaceDomain, consoleOutput)
+}
+
+// SaveSnapshot Saves a compressed snapshot of the site's database along with a manifest describing the site.
+func (s *Site) SaveSnapshot(name string, consoleOutput *console.Console) (SnapshotManifest, error) {
+	created := time.Now()
+
+	if name == "" {
+		name = created.Format("20060102-150405")
+	}
+
+	if !validSnapshotName.MatchString(name) {
+		return SnapshotManifest{}, fmt.Errorf(
+			"the snapshot name, %s, is invalid. Please use only letters, numbers, periods, dashes and underscores", name)
+	}
+
+	snapshotDirectory := s.getSnapshotDirectory(s.settings.Get("name"))
+
+	exists, err := helpers.PathExists(filepath.Join(snapshotDirectory, name+".json"))
+	if err != nil {
+		return SnapshotManifest{}, err
+	}
+
+	if exists {
+		return SnapshotManifest{}, fmt.Errorf("a snapshot named %s already exists. Please choose a different name", name)
+	}
+
+	manifest, err := s.getSnapshotManifest(name, created, consoleOutput)
+	if err != nil {
+		return manifest, err
+	}
+
+	consoleOutput.Println("Exporting the database.")
+
+	err = s.exportDatabaseToSite(snapshotTempFile, consoleOutput)
+	if err != nil {
+		return manifest, err
+	}
+
+	tempFile := filepath.Join(s.settings.Get("siteDirectory"), snapshotTempFile)
+
+	defer os.Remove(tempFile)
+
+	err = os.MkdirAll(snapshotDirectory, os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return manifest, err
+	}
+
+	err = helpers.GzipFile(tempFile, filepath.Join(snapshotDirectory, name+".sql.gz"))
+	if err != nil {
+		return manifest, err
+	}
+
+	return manifest, writeJSONFile(filepath.Join(snapshotDirectory, name+".json"), manifest)
+}
+
+// getSnapshotDirectory Returns the directory snapshots are stored in for the given site.
+func (s *Site) getSnapshotDirectory(siteName string) string {
+	return filepath.Join(s.settings.Get("appDirectory"), "sites", siteName, "snapshots")
+}
+
+// getSnapshotManifest Collects the versions and plugins of the running site for a new snapshot.
+func (s *Site) getSnapshotManifest(name string, created time.Time, consoleOutput *console.Console) (SnapshotManifest, error) {
+	manifest := SnapshotManifest{
+		Name:            name,
+		Site:            s.settings.Get("name"),
+		Domain:          s.settings.GetDomain(),
+		Created:         created,
+		Database:        s.settings.Get("database"),
+		DatabaseVersion: s.settings.Get("databaseVersion"),
+		Plugins:         []string{},
+	}
+
+	code, output, err := s.WPCli([]string{"core", "version"}, false, consoleOutput)
+	if err != nil || code != 0 {
+		return manifest, wpCliError("unable to determine the WordPress version", err, output)
+	}
+
+	manifest.WordPressVersion = strings.TrimSpace(output)
+
+	phpOutput, err := s.WordPress("php -r 'echo PHP_VERSION;'", false, false)
+	if err != nil {
+		return manifest, err
+	}
+
+	manifest.PHPVersion = strings.TrimSpace(phpOutput.StdOut)
+
+	isUsingSQLite, err := s.isUsingSQLite()
+	if err != nil {
+		return manifest, err
+	}
+
+	if isUsingSQLite {
+		manifest.Database = "sqlite"
+		manifest.DatabaseVersion = ""
+	} else {
+		code, output, err = s.WPCli([]string{"db", "query", "SELECT VERSION();", "--skip-column-names"}, false, consoleOutput)
+		if err == nil && code == 0 {
+			manifest.DatabaseVersion = strings.TrimSpace(output)
+		}
+	}
+
+	code, output, err = s.WPCli([]string{"plugin", "list", "--status=active", "--field=name"}, false, consoleOutput)
+	if err != nil || code != 0 {
+		return manifest, wpCliError("unable to list the active plugins", err, output)
+	}
+
+	for _, plugin := range strings.Split(output, "\n") {
+		plugin = strings.TrimSpace(plugin)
+
+		if plugin != "" {
+			manifest.Plugins = append(manifest.Plugins, plugin)
+		}
+	}
+
+	return manifest, nil
+}
+
+// readSnapshotManifest Reads the manifest of a saved snapshot.
+func (s *Site) readSnapshotManifest(snapshotDirectory, name string) (SnapshotManifest, error) {
+	var manifest SnapshotManifest
+
+	if !validSnapshotName.MatchString(name) {
+		return manifest, fmt.Errorf("the snapshot, %s, does not exist", name)
+	}
+
+	err := readJSONFile(filepath.Join(snapshotDirectory, name+".json"), &manifest)
+	if err != nil && os.IsNotExist(err) {
+		return manifest, fmt.Errorf("the snapshot, %s, does not exist", name)
+	}
+
+	return manifest, err
+}
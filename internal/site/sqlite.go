@@ -0,0 +1,128 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+)
+
+const (
+	sqliteCommandPackage = "automattic/wp-cli-sqlite-command"
+	wpCliPackagesDir     = "wp-cli/packages"
+)
+
+// copySQLiteDatabase Copies the raw SQLite database file next to the requested export file when it can't be exported as SQL.
+func (s *Site) copySQLiteDatabase(exportFile string, exportErr error, consoleOutput *console.Console) (string, error) {
+	databaseFile, err := s.getSQLiteDatabaseFile()
+	if err != nil {
+		return "", err
+	}
+
+	rawExportFile := strings.TrimSuffix(exportFile, filepath.Ext(exportFile)) + ".sqlite"
+
+	consoleOutput.Warn(
+		fmt.Sprintf(
+			"The SQLite database could not be exported as SQL (%s). A copy of the raw database file has been exported instead.",
+			strings.TrimSpace(exportErr.Error())))
+
+	err = helpers.CopyFile(databaseFile, rawExportFile)
+	if err != nil {
+		return "", err
+	}
+
+	return rawExportFile, nil
+}
+
+// ensureSQLiteCommand Installs the wp-cli SQLite command in the site's wp-cli package directory if it isn't already there.
+func (s *Site) ensureSQLiteCommand(consoleOutput *console.Console) error {
+	packagePath := filepath.Join(s.settings.Get("siteDirectory"), wpCliPackagesDir, "vendor", sqliteCommandPackage)
+
+	hasPackage, err := helpers.PathExists(packagePath)
+	if err != nil || hasPackage {
+		return err
+	}
+
+	consoleOutput.Println("Installing the wp-cli SQLite command.")
+
+	code, output, err := s.WPCli([]string{"package", "install", sqliteCommandPackage}, false, consoleOutput)
+	if err != nil || code != 0 {
+		return wpCliError("unable to install the wp-cli SQLite command", err, output)
+	}
+
+	return nil
+}
+
+// exportSQLiteDatabase Exports the site's SQLite database as a MySQL compatible dump to the given file in the site directory.
+func (s *Site) exportSQLiteDatabase(fileName string, consoleOutput *console.Console) error {
+	err := s.ensureSQLiteCommand(consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	exportCommand := []string{
+		"sqlite",
+		"export",
+		path.Join("/Site", fileName),
+	}
+
+	code, output, err := s.WPCli(exportCommand, false, consoleOutput)
+	if err != nil || code != 0 {
+		return wpCliError("database export failed", err, output)
+	}
+
+	return nil
+}
+
+// getSQLiteDatabaseFile Returns the path to the site's SQLite database on the host.
+func (s *Site) getSQLiteDatabaseFile() (string, error) {
+	wordPressDirectory, err := s.getWordPressDirectory()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(wordPressDirectory, "wp-content", "database", ".ht.sqlite"), nil
+}
+
+// importSQLiteDatabase Loads a MySQL dump from the given file in the site directory into the site's SQLite database.
+func (s *Site) importSQLiteDatabase(fileName string, preserve bool, consoleOutput *console.Console) error {
+	err := s.ensureSQLiteCommand(consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	if !preserve {
+		consoleOutput.Println("Dropping the existing database.")
+
+		var databaseFile string
+
+		databaseFile, err = s.getSQLiteDatabaseFile()
+		if err != nil {
+			return err
+		}
+
+		err = os.Remove(databaseFile)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	consoleOutput.Println("Importing the database file.")
+
+	importCommand := []string{
+		"sqlite",
+		"import",
+		path.Join("/Site", fileName),
+	}
+
+	code, output, err := s.WPCli(importCommand, false, consoleOutput)
+	if err != nil || code != 0 {
+		return wpCliError("database import failed", err, output)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package site
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/docker"
+
+	"github.com/docker/docker/api/types/mount"
+)
+
+// Tool represents a command line tool Kana can run in its official Docker image.
+type Tool struct {
+	Name, Image, VersionSetting string
+}
+
+var tools = []Tool{
+	{Name: "composer", Image: "composer", VersionSetting: "composerVersion"},
+	{Name: "npm", Image: "node", VersionSetting: "nodeVersion"},
+}
+
+// GetTools Returns the tools that can be run with Kana.
+func GetTools() []Tool {
+	return tools
+}
+
+// RunTool Runs a tool such as Composer or npm in the working directory as the current user and returns its exit code.
+func (s *Site) RunTool(tool Tool, args []string, consoleOutput *console.Console) (int64, error) {
+	cacheDirectory := filepath.Join(s.settings.Get("appDirectory"), "cache", tool.Name)
+
+	err := os.MkdirAll(cacheDirectory, os.FileMode(defaultDirPermissions))
+	if err != nil {
+		return 1, err
+	}
+
+	command := []string{tool.Name}
+	command = append(command, args...)
+
+	toolContainer := docker.ContainerConfig{
+		Name:        fmt.Sprintf("kana-%s-%s", s.settings.Get("name"), tool.Name),
+		Image:       fmt.Sprintf("%s:%s", tool.Image, s.settings.Get(tool.VersionSetting)),
+		NetworkName: "kana",
+		HostName:    fmt.Sprintf("kana-%s-%s", s.settings.Get("name"), tool.Name),
+		Command:     command,
+		WorkingDir:  "/app",
+		Env: []string{
+			"HOME=/tmp",
+			"COMPOSER_CACHE_DIR=/tmp/cache",
+			"npm_config_cache=/tmp/cache",
+		},
+		Volumes: []mount.Mount{
+			{ // The project the tool works on
+				Type:   mount.TypeBind,
+				Source: s.settings.Get("workingDirectory"),
+				Target: "/app",
+			},
+			{ // Share downloads between runs and sites
+				Type:   mount.TypeBind,
+				Source: cacheDirectory,
+				Target: "/tmp/cache",
+			},
+		},
+	}
+
+	_, _, err = s.dockerClient.EnsureNetwork("kana")
+	if err != nil {
+		return 1, err
+	}
+
+	err = s.dockerClient.EnsureImage(toolContainer.Image, s.settings.Get("appDirectory"), s.settings.GetInt("updateInterval"), consoleOutput)
+	if err != nil {
+		return 1, err
+	}
+
+	code, _, err := s.dockerClient.ContainerRunAndClean(&toolContainer, true)
+
+	return code, err
+}
@@ -29,25 +29,33 @@ func (s *Site) maybeStopTraefik() error {
 	return nil
 }
 
-// startTraefik Starts the Traefik container.
-func (s *Site) startTraefik(consoleOutput *console.Console) error {
-	err := settings.EnsureSSLCerts(s.settings.Get("appDirectory"), s.settings.GetBool("SSL"), consoleOutput)
+// prepareTraefik Makes sure the certificates and network Traefik needs exist and returns its configuration.
+func (s *Site) prepareTraefik(consoleOutput *console.Console) (docker.ContainerConfig, error) {
+	sharedCertChanged, err := settings.EnsureSSLCerts(s.settings.Get("appDirectory"), s.settings.GetBool("SSL"), consoleOutput)
 	if err != nil {
-		return err
+		return docker.ContainerConfig{}, err
 	}
 
-	_, _, err = s.dockerClient.EnsureNetwork("kana")
+	siteCertChanged, err := settings.EnsureSiteCert(s.settings.Get("appDirectory"), s.settings.Get("name"), s.settings.GetCertDomains())
 	if err != nil {
-		return err
+		return docker.ContainerConfig{}, err
 	}
 
-	err = s.dockerClient.EnsureImage(
-		"traefik:"+traefikVersion,
-		s.settings.Get("appDirectory"),
-		s.settings.GetInt("updateInterval"),
-		consoleOutput)
+	// Traefik only reads certificates when it starts
+	if sharedCertChanged || siteCertChanged {
+		if s.dockerClient.IsContainerRunning(traefikContainerName) {
+			consoleOutput.Println("Restarting Traefik to load the new certificates. Other running sites will be unavailable for a few seconds.")
+		}
+
+		err = s.RestartTraefik()
+		if err != nil {
+			return docker.ContainerConfig{}, err
+		}
+	}
+
+	_, _, err = s.dockerClient.EnsureNetwork("kana")
 	if err != nil {
-		return err
+		return docker.ContainerConfig{}, err
 	}
 
 	traefikPorts := []docker.ExposedPorts{
@@ -89,17 +97,33 @@ func (s *Site) startTraefik(consoleOutput *console.Console) error {
 		},
 	}
 
-	_, err = s.dockerClient.ContainerRun(&traefikConfig, false, false)
+	return traefikConfig, nil
+}
+
+// RestartTraefik Restarts Traefik, if it is running, so it picks up new certificates.
+func (s *Site) RestartTraefik() error {
+	_, err := s.dockerClient.ContainerRestart(traefikContainerName)
+
 	return err
 }
 
-// stopTraefik Stops the Traefik container.
+// stopTraefik Stops the Traefik container, and Kana's DNS server unless systemd-resolved is still pointed at it.
 func (s *Site) stopTraefik() error {
 	_, err := s.dockerClient.ContainerStop(traefikContainerName)
 	if err != nil {
 		return err
 	}
 
+	// systemd-resolved sends queries to the DNS server until the drop-in is removed so leave it, and its network, running
+	if settings.IsDNSResolverInstalled() {
+		return nil
+	}
+
+	err = s.StopDNS()
+	if err != nil {
+		return err
+	}
+
 	// Delete the "kana" network as well
 	_, err = s.dockerClient.RemoveNetwork("kana")
 
@@ -1,7 +1,6 @@
 package site
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -16,8 +15,9 @@ import (
 )
 
 type PluginInfo struct {
-	Name   string `json:"name"`
-	Status string `json:"status"`
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Version string `json:"version"`
 }
 
 var defaultDirPermissions = 0750
@@ -46,47 +46,6 @@ func (s *Site) getWordPressDirectory() (wordPressDirectory string, err error) {
 	return wordPressDirectory, err
 }
 
-// getInstalledWordPressPlugins Returns list of installed plugins and whether default plugins are still present.
-func (s *Site) getInstalledWordPressPlugins(consoleOutput *console.Console) (pluginList []string, hasDefaultPlugins bool, err error) {
-	commands := []string{
-		"plugin",
-		"list",
-		"--format=json",
-	}
-
-	hasDefaultPlugins = false
-
-	_, commandOutput, err := s.WPCli(commands, false, consoleOutput)
-	if err != nil {
-		return []string{}, true, err
-	}
-
-	rawPlugins := []PluginInfo{}
-	plugins := []string{}
-
-	err = json.Unmarshal([]byte(commandOutput), &rawPlugins)
-	if err != nil {
-		return []string{}, true, err
-	}
-
-	for _, plugin := range rawPlugins {
-		if plugin.Status != "dropin" &&
-			plugin.Status != "must-use" &&
-			plugin.Name != s.settings.Get("name") &&
-			plugin.Name != "hello" &&
-			plugin.Name != "akismet" {
-			plugins = append(plugins, plugin.Name)
-		}
-
-		if plugin.Name == "hello" ||
-			plugin.Name == "akismet" {
-			hasDefaultPlugins = true
-		}
-	}
-
-	return plugins, hasDefaultPlugins, nil
-}
-
 func (s *Site) getWordPressMounts(appDir string) ([]mount.Mount, error) {
 	appVolumes := []mount.Mount{
 		{ // The root directory of the WordPress site
@@ -140,11 +99,23 @@ func (s *Site) getWordPressMounts(appDir string) ([]mount.Mount, error) {
 		})
 	}
 
-	return appVolumes, nil
+	extensionMounts, err := s.getExtensionMounts(appDir)
+	if err != nil {
+		return appVolumes, err
+	}
+
+	localMounts, err := s.getLocalMountVolumes(appDir)
+	if err != nil {
+		return appVolumes, err
+	}
+
+	appVolumes = append(appVolumes, extensionMounts...)
+
+	return append(appVolumes, localMounts...), nil
 }
 
 func (s *Site) getWordPressContainer(appVolumes []mount.Mount, appContainers []docker.ContainerConfig) []docker.ContainerConfig {
-	hostRule := fmt.Sprintf("Host(`%[1]s`)", s.settings.GetDomain())
+	hostRule := s.getHostRule()
 
 	envVars := []string{
 		"IS_KANA_ENVIRONMENT=true",
@@ -168,7 +139,7 @@ func (s *Site) getWordPressContainer(appVolumes []mount.Mount, appContainers []d
 
 	wordPressContainer := docker.ContainerConfig{
 		Name:        fmt.Sprintf("kana-%s-wordpress", s.settings.Get("name")),
-		Image:       fmt.Sprintf("wordpress:php%s", s.settings.Get("php")),
+		Image:       s.getWordPressImage(),
 		NetworkName: "kana",
 		Ports:       defaultPorts,
 		HostName:    fmt.Sprintf("kana-%s-wordpress", s.settings.Get("name")),
@@ -184,12 +155,19 @@ func (s *Site) getWordPressContainer(appVolumes []mount.Mount, appContainers []d
 			"kana.site": s.settings.Get("name"),
 		},
 		Volumes: appVolumes,
+		// Apache answers once PHP is running and WordPress can reach its database
+		HealthCheck: s.getHealthCheck("curl -fsS -o /dev/null http://localhost/ || exit 1"),
 	}
 
 	if s.settings.GetBool("AutomaticLogin") {
 		wordPressContainer.Env = append(wordPressContainer.Env, "KANA_ADMIN_LOGIN=true")
 	}
 
+	// Docker Desktop provides host.docker.internal but plain Docker on Linux needs it mapped for Xdebug to reach the IDE
+	if runtime.GOOS == "linux" {
+		wordPressContainer.ExtraHosts = []string{"host.docker.internal:host-gateway"}
+	}
+
 	if s.settings.GetBool("WPDebug") {
 		wordPressContainer.Env = append(wordPressContainer.Env, "WORDPRESS_DEBUG=1")
 	}
@@ -200,6 +178,11 @@ func (s *Site) getWordPressContainer(appVolumes []mount.Mount, appContainers []d
 		extraConfig += "define( 'SCRIPT_DEBUG', true );"
 	}
 
+	// The object cache drop-in loads before the Kana mu-plugin so it needs the host from wp-config.php
+	if s.settings.GetBool("redis") {
+		extraConfig += s.getRedisConfig()
+	}
+
 	wordPressContainer.Env = append(wordPressContainer.Env, extraConfig)
 
 	appContainers = append(appContainers, wordPressContainer)
@@ -214,6 +197,7 @@ func (s *Site) getWordPressContainers() []string {
 		fmt.Sprintf("kana-%s-wordpress", s.settings.Get("name")),
 		fmt.Sprintf("kana-%s-phpmyadmin", s.settings.Get("name")),
 		fmt.Sprintf("kana-%s-mailpit", s.settings.Get("name")),
+		fmt.Sprintf("kana-%s-redis", s.settings.Get("name")),
 	}
 }
 
@@ -247,65 +231,84 @@ func (s *Site) activateProject(consoleOutput *console.Console) error {
 	return nil
 }
 
-func (s *Site) activateTheme(consoleOutput *console.Console) error {
-	if s.settings.Get("type") == "theme" || s.settings.Get("theme") == "" {
-		return nil
+// installPluginsAndTheme Installs the site's plugins and default theme, and activates any mounts, in a single wp-cli container.
+func (s *Site) installPluginsAndTheme(consoleOutput *console.Console) error {
+	// The lists run in their own batch as the install commands depend on the versions already installed
+	installedPlugins, installedThemes, err := s.getInstalledExtensions(consoleOutput)
+	if err != nil {
+		return err
 	}
 
-	consoleOutput.Println(fmt.Sprintf("Installing default theme:  %s", consoleOutput.Bold(consoleOutput.Blue(s.settings.Get("theme")))))
+	commands := [][]string{}
+	failureMessages := []string{}
 
-	setupCommand := []string{
-		"theme",
-		"install",
-		"--activate",
-		s.settings.Get("theme"),
-	}
+	for _, kind := range []string{"plugin", "theme"} {
+		extensions, err := s.getExtensions(kind)
+		if err != nil {
+			return err
+		}
 
-	code, _, err := s.WPCli(setupCommand, false, consoleOutput)
-	if err != nil {
-		return err
-	}
+		installed, message := installedPlugins, "Installing plugin:  %s"
 
-	if code != 0 {
-		consoleOutput.Warn(fmt.Sprintf("Unable to install theme: %s.", consoleOutput.Bold(consoleOutput.Blue(s.settings.Get("theme")))))
-	}
+		if kind == "theme" {
+			installed, message = installedThemes, "Installing default theme:  %s"
+		}
 
-	return nil
-}
+		for _, extension := range extensions {
+			command := getExtensionCommand(kind, extension, installed)
+
+			// Don't try to reinstall the plugin if it is already installed
+			if command == nil {
+				continue
+			}
+
+			consoleOutput.Println(fmt.Sprintf(message, consoleOutput.Bold(consoleOutput.Blue(extension.Entry))))
+
+			failureMessage := fmt.Sprintf("Unable to install %s: %s.", kind, consoleOutput.Bold(consoleOutput.Blue(extension.Entry)))
 
-// installDefaultPlugins Installs a list of WordPress plugins.
-func (s *Site) installDefaultPlugins(consoleOutput *console.Console) error {
-	installedPlugins, _, err := s.getInstalledWordPressPlugins(consoleOutput)
+			if extension.Source == settings.ExtensionSourceGit {
+				err = s.syncGitExtension(kind, extension, consoleOutput)
+				if err != nil {
+					consoleOutput.Warn(fmt.Sprintf("%s %s", failureMessage, err))
+					continue
+				}
+			}
+
+			commands = append(commands, command)
+			failureMessages = append(failureMessages, failureMessage)
+		}
+	}
+
+	localMounts, err := s.getLocalMounts()
 	if err != nil {
 		return err
 	}
 
-	for _, plugin := range s.settings.GetSlice("plugins") {
-		setupCommand := []string{
-			"plugin",
-			"install",
-			"--activate",
-			plugin,
+	for _, localMount := range localMounts {
+		if !localMount.Activate {
+			continue
 		}
 
-		// Don't  try to reinstall the plugin if it is already installed
-		for _, installedPlugin := range installedPlugins {
-			if installedPlugin == plugin {
-				setupCommand = []string{}
-			}
-		}
+		consoleOutput.Println(
+			fmt.Sprintf("Activating %s:  %s", localMount.Type, consoleOutput.Bold(consoleOutput.Blue(localMount.Slug))))
 
-		if len(setupCommand) > 0 {
-			consoleOutput.Println(fmt.Sprintf("Installing plugin:  %s", consoleOutput.Bold(consoleOutput.Blue(plugin))))
+		commands = append(commands, []string{localMount.Type, "activate", localMount.Slug})
+		failureMessages = append(failureMessages,
+			fmt.Sprintf("Unable to activate %s: %s.", localMount.Type, consoleOutput.Bold(consoleOutput.Blue(localMount.Slug))))
+	}
 
-			code, _, err := s.WPCli(setupCommand, false, consoleOutput)
-			if err != nil {
-				return err
-			}
+	if len(commands) == 0 {
+		return nil
+	}
 
-			if code != 0 {
-				consoleOutput.Warn(fmt.Sprintf("Unable to install plugin: %s.", consoleOutput.Bold(consoleOutput.Blue(plugin))))
-			}
+	results, err := s.WPCliBatch(commands, consoleOutput)
+	if err != nil {
+		return err
+	}
+
+	for i, result := range results {
+		if result.Code != 0 {
+			consoleOutput.Warn(failureMessages[i])
 		}
 	}
 
@@ -395,16 +398,16 @@ func (s *Site) installWordPress(consoleOutput *console.Console) error {
 	return nil
 }
 
-// startWordPress Starts the WordPress containers.
-func (s *Site) startWordPress(consoleOutput *console.Console) error {
+// prepareWordPress Writes the files the WordPress containers mount and returns their configuration.
+func (s *Site) prepareWordPress() ([]docker.ContainerConfig, error) {
 	_, _, err := s.dockerClient.EnsureNetwork("kana")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	appDir, databaseDir, err := s.getDirectories()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// Replace wp-config.php with the container's file
@@ -415,30 +418,64 @@ func (s *Site) startWordPress(consoleOutput *console.Console) error {
 
 	appVolumes, err := s.getWordPressMounts(appDir)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	// PHP's configuration and extensions are applied fresh on every start so changes to the settings always take effect
+	err = s.writePHPIni()
+	if err != nil {
+		return nil, err
 	}
 
+	err = s.ensureXdebugIni()
+	if err != nil {
+		return nil, err
+	}
+
+	appVolumes = append(appVolumes, s.getPHPIniMounts()...)
+	appVolumes = append(appVolumes, s.getXdebugMount())
+
 	var appContainers []docker.ContainerConfig
 
 	appContainers = s.getDatabaseContainer(databaseDir, appContainers)
 	appContainers = s.getWordPressContainer(appVolumes, appContainers)
 
+	return appContainers, nil
+}
+
+// startWordPress Starts the WordPress containers.
+func (s *Site) startWordPress(consoleOutput *console.Console) error {
+	appContainers, err := s.prepareWordPress()
+	if err != nil {
+		return err
+	}
+
+	err = s.ensureWordPressImage(consoleOutput)
+	if err != nil {
+		return err
+	}
+
 	for i := range appContainers {
-		err := s.startContainer(&appContainers[i], true, true, consoleOutput)
+		err = s.startContainer(&appContainers[i], true, true, consoleOutput)
 		if err != nil {
 			return err
 		}
 	}
 
-	// Set PHP's upload_max_filesize to 200m to make it easier to work with WordPress.
-	command := "echo 'upload_max_filesize = 200m' >> /usr/local/etc/php/conf.d/z-custom.ini"
+	return s.waitForWordPress(appContainers)
+}
 
-	_, err = s.WordPress(command, true, true)
-	if err != nil {
-		return err
+// waitForWordPress Waits for the database and WordPress containers to pass their health checks.
+func (s *Site) waitForWordPress(appContainers []docker.ContainerConfig) error {
+	// On slow filesystems the database can take a while to initialize the first time
+	for i := range appContainers {
+		err := s.dockerClient.WaitHealthy(appContainers[i].Name, s.getHealthCheckTimeout())
+		if err != nil {
+			return err
+		}
 	}
 
-	return s.verifyDatabase(consoleOutput) // verify the database is ready for connections. On slow filesystems this can take a few seconds.
+	return nil
 }
 
 // resetWPFilePermissions Ensures the www-data user owns the WordPress directory.
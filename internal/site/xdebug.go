@@ -1,63 +1,154 @@
 package site
 
 import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
 	"strings"
 
 	"github.com/ChrisWiegman/kana/internal/console"
+	"github.com/ChrisWiegman/kana/internal/helpers"
+	"github.com/ChrisWiegman/kana/internal/settings"
+
+	"github.com/docker/docker/api/types/mount"
 )
 
+const xdebugIniFile = "zz-kana-xdebug.ini"
+
 // IsXdebugRunning returns true if Xdebug is already running or false if not.
 func (s *Site) IsXdebugRunning(consoleOutput *console.Console) bool {
-	output, err := s.WordPress("pecl list | grep xdebug", false, false)
+	output, err := s.WordPress("php -m | grep -i xdebug", false, false)
 	if err != nil {
 		return false
 	}
 
-	return strings.Contains(output.StdOut, "xdebug")
+	return strings.Contains(strings.ToLower(output.StdOut), "xdebug")
+}
+
+// StartXdebug turns on Xdebug in the site's PHP container, optionally with a mode other than the xdebugMode setting.
+func (s *Site) StartXdebug(mode string, consoleOutput *console.Console) error {
+	if mode != "" {
+		err := s.settings.Set("xdebugMode", mode)
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.setXdebug(true, consoleOutput)
+}
+
+// StopXdebug turns off Xdebug in the site's PHP container.
+func (s *Site) StopXdebug(consoleOutput *console.Console) error {
+	return s.setXdebug(false, consoleOutput)
+}
+
+// ensureXdebugIni Creates the Xdebug ini file, turned off, if it doesn't exist yet so it can be mounted.
+func (s *Site) ensureXdebugIni() error {
+	hasIni, err := helpers.PathExists(s.getXdebugMount().Source)
+	if err != nil || hasIni {
+		return err
+	}
+
+	return s.writeXdebugIni(false)
+}
+
+// getXdebugIni Returns the ini file that loads and configures Xdebug, which is already installed in Kana's WordPress image.
+func (s *Site) getXdebugIni() string {
+	xdebugIni := []string{
+		"zend_extension=xdebug",
+		fmt.Sprintf("xdebug.mode=%s", strings.ReplaceAll(s.settings.Get("xdebugMode"), " ", "")),
+		fmt.Sprintf("xdebug.client_host=%s", s.settings.Get("xdebugClientHost")),
+		fmt.Sprintf("xdebug.client_port=%d", s.settings.GetInt("xdebugPort")),
+		"xdebug.start_with_request=trigger",
+		"xdebug.show_local_vars=1",
+		fmt.Sprintf("xdebug.output_dir=%s", path.Join("/Site", profilesDirectory)),
+		"xdebug.profiler_output_name=cachegrind.out.%t.%r",
+		"xdebug.trace_output_name=trace.%t.%r",
+		"xdebug.use_compression=false",
+		"html_errors=On",
+	}
+
+	if s.settings.Get("xdebugIdeKey") != "" {
+		xdebugIni = append(xdebugIni, fmt.Sprintf("xdebug.idekey=%s", s.settings.Get("xdebugIdeKey")))
+	}
+
+	return strings.Join(xdebugIni, "\n") + "\n"
 }
 
-// StartXdebug installs and starts xdebug in the site's PHP container.
-func (s *Site) StartXdebug(consoleOutput *console.Console) error {
-	commands := []string{
-		"pecl list | grep xdebug",
-		"pecl install xdebug",
-		"docker-php-ext-enable xdebug",
-		"echo 'xdebug.discover_client_host=true >> /usr/local/etc/php/php.ini",
-		"echo 'xdebug.client_host=host.docker.internal' >> /usr/local/etc/php/php.ini",
-		"echo 'xdebug.mode=debug,develop,trace,gcstats,profile' >> /usr/local/etc/php/php.ini",
-		"echo 'xdebug.discover_client_host=on' >> /usr/local/etc/php/php.ini",
-		"echo 'xdebug.start_with_request=trigger' >> /usr/local/etc/php/php.ini",
-		"echo 'xdebug.show_local_vars=1' >> /usr/local/etc/php/php.ini",
-		"echo 'html_errors = On' >> /usr/local/etc/php/conf.d/z-custom.ini", // Ensure custom overrides happen
+// getXdebugMount Returns the mount for the ini file that turns Xdebug on or off.
+func (s *Site) getXdebugMount() mount.Mount {
+	return mount.Mount{
+		Type:     mount.TypeBind,
+		Source:   filepath.Join(s.settings.Get("siteDirectory"), "php", xdebugIniFile),
+		Target:   path.Join(phpConfDirectory, xdebugIniFile),
+		ReadOnly: true,
 	}
+}
 
-	for i, command := range commands {
-		restart := false
+// hasXdebugMount Checks if the running WordPress container was started with Kana's Xdebug ini file.
+func (s *Site) hasXdebugMount() bool {
+	mounts := s.dockerClient.ContainerGetMounts(fmt.Sprintf("kana-%s-wordpress", s.settings.Get("name")))
 
-		if i+1 == len(commands) {
-			restart = true
+	for _, containerMount := range mounts {
+		if containerMount.Destination == s.getXdebugMount().Target {
+			return true
 		}
+	}
+
+	return false
+}
+
+// setXdebug Flips the Xdebug ini file and gracefully reloads Apache so the change takes effect without a restart.
+func (s *Site) setXdebug(enable bool, consoleOutput *console.Console) error {
+	err := s.writeXdebugIni(enable)
+	if err != nil {
+		return err
+	}
 
-		output, err := s.WordPress(command, restart, true)
+	// Containers started before Kana built its own image need to be recreated to pick up the ini file
+	if !s.hasXdebugMount() {
+		err = s.stopWordPress()
 		if err != nil {
 			return err
 		}
 
-		// Verify that the command ran correctly
-		if i == 0 && strings.Contains(output.StdOut, "xdebug") {
-			return nil
-		}
+		return s.startWordPress(consoleOutput)
+	}
+
+	return s.reloadApache()
+}
+
+// reloadApache Gracefully reloads Apache in the WordPress container so PHP picks up changes to its ini files.
+func (s *Site) reloadApache() error {
+	output, err := s.WordPress("apache2ctl graceful", false, true)
+	if err != nil {
+		return err
+	}
+
+	if output.ExitCode != 0 {
+		return fmt.Errorf("unable to reload Apache: %s", strings.TrimSpace(output.StdErr))
 	}
 
 	return nil
 }
 
-// StopXdebug stops Xdebug by restarting the WordPress containers.
-func (s *Site) StopXdebug(consoleOutput *console.Console) error {
-	err := s.stopWordPress()
+// writeXdebugIni Writes the Xdebug ini file in place so the bind mount in a running container sees the change.
+func (s *Site) writeXdebugIni(enable bool) error {
+	contents := "; Xdebug is off. Use `kana xdebug on` to turn it on.\n"
+
+	if enable {
+		contents = s.getXdebugIni()
+	}
+
+	phpDirectory := filepath.Join(s.settings.Get("siteDirectory"), "php")
+
+	err := os.MkdirAll(phpDirectory, os.FileMode(defaultDirPermissions))
 	if err != nil {
 		return err
 	}
 
-	return s.startWordPress(consoleOutput)
+	_, filePerms := settings.GetDefaultFilePermissions()
+
+	return os.WriteFile(filepath.Join(phpDirectory, xdebugIniFile), []byte(contents), os.FileMode(filePerms))
 }
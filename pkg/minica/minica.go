@@ -3,9 +3,10 @@ package minica
 import (
 	"bytes"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
-	"crypto/rsa"
-	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
@@ -13,8 +14,10 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"net"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 )
 
@@ -36,7 +39,10 @@ var fileOpenMode = 0600
 var hundredYears = 100
 var twoYears = 2
 var thirtyDays = 30
-var certBytes = 2048
+var skidBytes = 20
+
+// Leaf certificates are replaced once they are this close to expiring.
+var renewBefore = time.Duration(thirtyDays) * 24 * time.Hour
 
 func GenCerts(certInfo *CertInfo) error {
 	caKey := filepath.Join(certInfo.CertDir, certInfo.RootKey)
@@ -50,11 +56,97 @@ func GenCerts(certInfo *CertInfo) error {
 		return err
 	}
 
-	_, err = sign(issuer, domains, certInfo.CertDir, certInfo.SiteCert, certInfo.SiteKey)
+	_, err = sign(issuer, domains, nil, certInfo.CertDir, certInfo.SiteCert, certInfo.SiteKey)
 
 	return err
 }
 
+// EnsureCert Makes sure the site certificate covers exactly the given domains and IP addresses, was signed by the
+// current root certificate and isn't close to expiring, signing a new one if not. Returns true if it was replaced.
+func EnsureCert(certInfo *CertInfo, domains []string, ipAddresses []net.IP) (bool, error) {
+	caKey := filepath.Join(certInfo.CertDir, certInfo.RootKey)
+	caCert := filepath.Join(certInfo.CertDir, certInfo.RootCert)
+
+	issuer, err := getIssuer(caKey, caCert)
+	if err != nil {
+		return false, err
+	}
+
+	certContents, err := os.ReadFile(filepath.Join(certInfo.CertDir, certInfo.SiteCert))
+	if err == nil {
+		cert, err := readCert(certContents)
+		if err == nil && isCertCurrent(cert, issuer, domains, ipAddresses) {
+			return false, nil
+		}
+	} else if !os.IsNotExist(err) {
+		return false, err
+	}
+
+	return true, SignCert(certInfo, domains, ipAddresses)
+}
+
+// SignCert Replaces the site certificate and key with new ones, signed by the root certificate, covering the given
+// domains and IP addresses.
+func SignCert(certInfo *CertInfo, domains []string, ipAddresses []net.IP) error {
+	if len(domains) == 0 {
+		return fmt.Errorf("a certificate needs at least one domain")
+	}
+
+	caKey := filepath.Join(certInfo.CertDir, certInfo.RootKey)
+	caCert := filepath.Join(certInfo.CertDir, certInfo.RootCert)
+
+	issuer, err := getIssuer(caKey, caCert)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range []string{certInfo.SiteCert, certInfo.SiteKey} {
+		err = os.Remove(filepath.Join(certInfo.CertDir, file))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	_, err = sign(issuer, domains, ipAddresses, certInfo.CertDir, certInfo.SiteCert, certInfo.SiteKey)
+
+	return err
+}
+
+// RenewCert Replaces the site certificate and key with new ones covering the same domains and IP addresses, signed by
+// the current root certificate.
+func RenewCert(certInfo *CertInfo) error {
+	certContents, err := os.ReadFile(filepath.Join(certInfo.CertDir, certInfo.SiteCert))
+	if err != nil {
+		return err
+	}
+
+	cert, err := readCert(certContents)
+	if err != nil {
+		return fmt.Errorf("reading certificate from %s: %s", certInfo.SiteCert, err)
+	}
+
+	return SignCert(certInfo, cert.DNSNames, cert.IPAddresses)
+}
+
+// isCertCurrent Returns true if a certificate can still be used as is for the given domains and IP addresses.
+func isCertCurrent(cert *x509.Certificate, iss *issuer, domains []string, ipAddresses []net.IP) bool {
+	if !slices.Equal(cert.DNSNames, domains) || len(cert.IPAddresses) != len(ipAddresses) {
+		return false
+	}
+
+	for i := range ipAddresses {
+		if !cert.IPAddresses[i].Equal(ipAddresses[i]) {
+			return false
+		}
+	}
+
+	if time.Now().Add(renewBefore).After(cert.NotAfter) {
+		return false
+	}
+
+	return cert.CheckSignatureFrom(iss.cert) == nil
+}
+
 func getIssuer(keyFile, certFile string) (*issuer, error) {
 	keyContents, keyErr := os.ReadFile(keyFile)
 	certContents, certErr := os.ReadFile(certFile)
@@ -93,15 +185,21 @@ func getIssuer(keyFile, certFile string) (*issuer, error) {
 	return &issuer{key, cert}, nil
 }
 
+// readPrivateKey Reads an ECDSA key, or an RSA key created by older versions of Kana.
 func readPrivateKey(keyContents []byte) (crypto.Signer, error) {
 	block, _ := pem.Decode(keyContents)
 	if block == nil {
 		return nil, fmt.Errorf("no PEM found")
-	} else if block.Type != "RSA PRIVATE KEY" && block.Type != "ECDSA PRIVATE KEY" {
-		return nil, fmt.Errorf("incorrect PEM type %s", block.Type)
 	}
 
-	return x509.ParsePKCS1PrivateKey(block.Bytes)
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+
+	return nil, fmt.Errorf("incorrect PEM type %s", block.Type)
 }
 
 func readCert(certContents []byte) (*x509.Certificate, error) {
@@ -129,13 +227,13 @@ func makeIssuer(keyFile, certFile string) error {
 	return nil
 }
 
-func makeKey(filename string) (*rsa.PrivateKey, error) {
-	key, err := rsa.GenerateKey(rand.Reader, certBytes)
+func makeKey(filename string) (*ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 	if err != nil {
 		return nil, err
 	}
 
-	der := x509.MarshalPKCS1PrivateKey(key)
+	der, err := x509.MarshalECPrivateKey(key)
 	if err != nil {
 		return nil, err
 	}
@@ -152,7 +250,7 @@ func makeKey(filename string) (*rsa.PrivateKey, error) {
 	}()
 
 	err = pem.Encode(file, &pem.Block{
-		Type:  "RSA PRIVATE KEY",
+		Type:  "EC PRIVATE KEY",
 		Bytes: der,
 	})
 
@@ -254,11 +352,13 @@ func calculateSKID(pubKey crypto.PublicKey) ([]byte, error) {
 		return nil, err
 	}
 
-	skid := sha1.Sum(spki.SubjectPublicKey.Bytes) //nolint:gosec
-	return skid[:], nil
+	// Use the leftmost 160 bits of the SHA-256 hash of the key, as described in RFC 7093
+	skid := sha256.Sum256(spki.SubjectPublicKey.Bytes)
+
+	return skid[:skidBytes], nil
 }
 
-func sign(iss *issuer, domains []string, certPath, siteCert, siteKey string) (*x509.Certificate, error) {
+func sign(iss *issuer, domains []string, ipAddresses []net.IP, certPath, siteCert, siteKey string) (*x509.Certificate, error) {
 	cn := domains[0]
 
 	key, err := makeKey(filepath.Join(certPath, siteKey))
@@ -271,8 +371,14 @@ func sign(iss *issuer, domains []string, certPath, siteCert, siteKey string) (*x
 		return nil, err
 	}
 
+	skid, err := calculateSKID(key.Public())
+	if err != nil {
+		return nil, err
+	}
+
 	template := &x509.Certificate{
-		DNSNames: domains,
+		DNSNames:    domains,
+		IPAddresses: ipAddresses,
 		Subject: pkix.Name{
 			CommonName: cn,
 		},
@@ -284,7 +390,8 @@ func sign(iss *issuer, domains []string, certPath, siteCert, siteKey string) (*x
 		// https://derflounder.wordpress.com/2019/06/06/new-tls-security-requirements-for-ios-13-and-macos-catalina-10-15/
 		NotAfter: time.Now().AddDate(twoYears, 0, thirtyDays),
 
-		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		SubjectKeyId:          skid,
+		KeyUsage:              x509.KeyUsageDigitalSignature,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
 		BasicConstraintsValid: true,
 		IsCA:                  false,
@@ -0,0 +1,101 @@
+package minica
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCertInfo(t *testing.T) *CertInfo {
+	t.Helper()
+
+	return &CertInfo{
+		CertDir:    t.TempDir(),
+		CertDomain: "sites.kana.sh",
+		RootKey:    "kana.root.key",
+		RootCert:   "kana.root.pem",
+		SiteCert:   "kana.site.pem",
+		SiteKey:    "kana.site.key",
+	}
+}
+
+func TestEnsureCert(t *testing.T) {
+	domains := []string{"test.sites.kana.sh", "*.test.sites.kana.sh"}
+	ipAddresses := []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")}
+
+	tests := []struct {
+		name        string
+		domains     []string
+		ipAddresses []net.IP
+		renewBefore time.Duration
+		expected    bool
+	}{
+		{"Current certificate", domains, ipAddresses, renewBefore, false},
+		{"Added domain", append([]string{"example.test"}, domains...), ipAddresses, renewBefore, true},
+		{"Removed domain", domains[:1], ipAddresses, renewBefore, true},
+		{"Changed IP addresses", domains, ipAddresses[:1], renewBefore, true},
+		{"Inside the renewal window", domains, ipAddresses, 3 * 365 * 24 * time.Hour, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			certInfo := newCertInfo(t)
+
+			changed, err := EnsureCert(certInfo, domains, ipAddresses)
+			assert.NoError(t, err)
+			assert.True(t, changed)
+
+			defaultRenewBefore := renewBefore
+			renewBefore = test.renewBefore
+
+			t.Cleanup(func() {
+				renewBefore = defaultRenewBefore
+			})
+
+			changed, err = EnsureCert(certInfo, test.domains, test.ipAddresses)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, changed)
+
+			// Only a certificate inside the renewal window is replaced every time
+			changed, err = EnsureCert(certInfo, test.domains, test.ipAddresses)
+			assert.NoError(t, err)
+			assert.Equal(t, test.renewBefore != defaultRenewBefore, changed)
+		})
+	}
+}
+
+func TestEnsureCertForeignIssuer(t *testing.T) {
+	domains := []string{"test.sites.kana.sh"}
+	certInfo := newCertInfo(t)
+	otherCertInfo := newCertInfo(t)
+
+	for _, info := range []*CertInfo{certInfo, otherCertInfo} {
+		_, err := EnsureCert(info, domains, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Replace the certificate with one for the same domains signed by another root certificate
+	otherCertContents, err := os.ReadFile(filepath.Join(otherCertInfo.CertDir, otherCertInfo.SiteCert))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(filepath.Join(certInfo.CertDir, certInfo.SiteCert), otherCertContents, os.FileMode(fileOpenMode))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := EnsureCert(certInfo, domains, nil)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+
+	changed, err = EnsureCert(certInfo, domains, nil)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+}